@@ -5,7 +5,6 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 
 	tasks "github.com/Eddy-Nio/task-tracker-cli/internal/task"
 	"github.com/spf13/cobra"
@@ -13,6 +12,13 @@ import (
 
 var (
 	title, description string
+	priority           string
+	due                string
+	tags               []string
+	parent             string
+	estimate           string
+	repeat             string
+	remind             string
 	testFile           string
 )
 
@@ -24,31 +30,110 @@ var addCmd = &cobra.Command{
 
 You can provide a detailed description of the task you want to add, which will be stored
 in a local JSON file. The unique identifier (UUID) for the task will be automatically generated
-and the task will be marked as 'todo' by default.`,
+and the task will be marked as 'todo' by default.
 
-	Run: func(cmd *cobra.Command, args []string) {
-		filename := "tasks.json"
+Use --priority to set how important the task is (low, medium, high, urgent or
+the short aliases l, m, h, u). Tasks are created with medium priority by default.
+
+Use --due to set when the task is due, either in the configured date format, as
+YYYY-MM-DD or as a phrase like "tomorrow", "next friday" or "in 3 days".
+Use --remind to have 'watch' announce the task that long before it is due,
+e.g. --remind 1h.
+
+Use --tag, once per tag, to label the task (e.g. --tag backend --tag urgent-fix).
+
+Use --estimate to set how much work the task is expected to take, e.g. 4h or
+1h30m. Work is recorded with 'start'/'stop' or 'log'.
+
+Use --parent with the ID (or a unique ID prefix) of another task to add the new
+task as one of its subtasks.
+
+Use --repeat to make the task recurring: daily, weekly, weekly:mon,thu, monthly,
+monthly:15 or an RRULE such as FREQ=WEEKLY;INTERVAL=2;BYDAY=MO. Marking the task
+as done adds its next occurrence, due on the next date of the rule.`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var (
+			storage *tasks.TaskStorage
+			err     error
+		)
 		if testFile != "" {
-			filename = testFile
+			storage, err = tasks.NewTaskStorage(testFile)
+		} else {
+			storage, err = newStorage()
 		}
-
-		storage, err := tasks.NewTaskStorage(filename)
 		if err != nil {
-			log.Fatalf("Error initializating storage file: %v", err)
+			return fmt.Errorf("error initializating storage file: %w", err)
+		}
+		defer storage.Close()
+
+		var opts []tasks.TaskOption
+		if cmd.Flags().Changed("priority") {
+			p, err := tasks.ValidatePriority(priority)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, tasks.WithPriority(p))
+		}
+		if cmd.Flags().Changed("due") {
+			dueAt, err := parseDate(due)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, tasks.WithDueAt(dueAt))
+		}
+		if cmd.Flags().Changed("remind") {
+			d, err := tasks.ParseDuration(remind)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, tasks.WithReminder(d))
+		}
+		if len(tags) > 0 {
+			opts = append(opts, tasks.WithTags(tags...))
+		}
+		if cmd.Flags().Changed("estimate") {
+			d, err := tasks.ParseDuration(estimate)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, tasks.WithEstimate(d))
+		}
+		if cmd.Flags().Changed("repeat") {
+			rule, err := tasks.ParseRecurrence(repeat)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, tasks.WithRecurrence(rule))
+		}
+		if parent != "" {
+			parentID, err := storage.ResolveID(parent)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, tasks.WithParent(parentID))
 		}
 
-		if _, err := storage.AddTask(title, description); err != nil {
-			log.Fatalf("Error when adding a new task: %v", err)
+		added, err := storage.AddTask(title, description, opts...)
+		if err != nil {
+			return fmt.Errorf("error when adding a new task: %w", err)
 		}
 
-		fmt.Printf("Task added successfully:\n")
-		storage.PrintTasks()
+		notice(cmd, "Task added successfully:\n")
+		return printTask(cmd, *added, storage)
 	},
 }
 
 func init() {
 	addCmd.Flags().StringVarP(&title, "title", "t", "", "Task title")
 	addCmd.Flags().StringVarP(&description, "description", "d", "", "Task description")
+	addCmd.Flags().StringVarP(&priority, "priority", "p", "", "Task priority (low/l, medium/med/m, high/h, urgent/u)")
+	addCmd.Flags().StringVar(&due, "due", "", "Due date (date format, YYYY-MM-DD, \"tomorrow\", \"next friday\", \"in 3 days\")")
+	addCmd.Flags().StringVar(&remind, "remind", "", "Remind this long before the due date, e.g. 1h or 30m (see 'watch')")
+	addCmd.Flags().StringArrayVar(&tags, "tag", nil, "Tag to attach to the task (repeatable)")
+	addCmd.Flags().StringVar(&estimate, "estimate", "", "Expected work, e.g. 4h or 1h30m")
+	addCmd.Flags().StringVar(&repeat, "repeat", "", "Recurrence rule, e.g. daily, weekly:mon,thu, monthly:15 or FREQ=WEEKLY;BYDAY=MO")
+	addCmd.Flags().StringVar(&parent, "parent", "", "Add the task as a subtask of this task ID or unique ID prefix")
 	addCmd.MarkFlagsRequiredTogether("title", "description")
 	addCmd.Flags().SortFlags = false
 	rootCmd.AddCommand(addCmd)
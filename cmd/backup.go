@@ -0,0 +1,106 @@
+/*
+Copyright © 2025 Eddy Nio
+*/
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// backupCmd represents the backup command
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Create, list and restore backups of your tasks",
+	Long: `The 'backup' command manages snapshots of your task list.
+
+Backups are JSON files stored in the configured backup directory (storage.backupDir).
+When task.autoBackup is enabled a snapshot is taken automatically before a change
+whenever the newest backup is older than task.backupInterval. Old backups are thinned
+out to the newest one per day (task.backupKeepDaily) and per week (task.backupKeepWeekly).
+
+Examples:
+  task-tracker backup create
+  task-tracker backup list
+  task-tracker backup restore tasks-20250101T120000.000Z.json`,
+}
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Take a backup of all tasks now",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storage, err := newStorage()
+		if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		}
+		defer storage.Close()
+
+		backup, err := storage.CreateBackup()
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Backup created: %s\n", backup.Name)
+		return nil
+	},
+}
+
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the available backups, newest first",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storage, err := newStorage()
+		if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		}
+		defer storage.Close()
+
+		backups, err := storage.ListBackups()
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		if len(backups) == 0 {
+			fmt.Fprintln(out, "No backups found")
+			return nil
+		}
+
+		for _, b := range backups {
+			fmt.Fprintf(out, "%s  %s  %d bytes\n", b.Name, b.CreatedAt.Local().Format(time.RFC3339), b.Size)
+		}
+		return nil
+	},
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Replace all tasks with the content of a backup",
+	Long: `Replace all tasks with the content of a backup.
+
+The current tasks are backed up first, so a restore can itself be reverted.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storage, err := newStorage()
+		if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		}
+		defer storage.Close()
+
+		if err := storage.RestoreBackup(args[0]); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Restored %d task(s) from %s\n", len(storage.ListTasks()), args[0])
+		return nil
+	},
+}
+
+func init() {
+	backupCmd.AddCommand(backupCreateCmd, backupListCmd, backupRestoreCmd)
+	rootCmd.AddCommand(backupCmd)
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupCommands(t *testing.T) {
+	storage, err := newStorage()
+	assert.NoError(t, err)
+	_, err = storage.AddTask("Backed up", "Task to snapshot")
+	assert.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+	})
+
+	rootCmd.SetArgs([]string{"backup", "create"})
+	assert.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), "Backup created: ")
+	name := strings.TrimSpace(strings.TrimPrefix(buf.String(), "Backup created: "))
+
+	buf.Reset()
+	rootCmd.SetArgs([]string{"backup", "list"})
+	assert.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), name)
+
+	buf.Reset()
+	rootCmd.SetArgs([]string{"backup", "restore", name})
+	assert.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), "Restored")
+
+	rootCmd.SetArgs([]string{"backup", "restore", "missing.json"})
+	assert.Error(t, rootCmd.Execute())
+}
@@ -4,31 +4,49 @@ Copyright © 2025 Eddy Nio
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"strings"
 
-	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
 	"github.com/spf13/cobra"
 )
 
+var clearYes bool
+
 var clearCmd = &cobra.Command{
 	Use:   "clear",
 	Short: "Clear all tasks",
-	Long:  `Clear all tasks from the storage file.`,
+	Long: `Clear all tasks from the storage file.
+
+You are asked for confirmation first unless --yes is given. The tasks can be
+brought back with 'undo'.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		storage, err := task.NewTaskStorage("tasks.json")
+		storage, err := newStorage()
 		if err != nil {
-			return fmt.Errorf("error initializing storage: file might be corrupt: %v", err)
+			return fmt.Errorf("error initializing storage: file might be corrupt: %w", err)
+		}
+		defer storage.Close()
+
+		out := cmd.OutOrStdout()
+		if !clearYes {
+			fmt.Fprint(out, "Are you sure you want to delete all tasks? You can revert it with 'undo' (y/n): ")
+			response, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+			if answer := strings.TrimSpace(response); answer != "y" && answer != "Y" {
+				return errors.New("operation cancelled by user")
+			}
 		}
 
 		if err := storage.ClearTasks(); err != nil {
-			return fmt.Errorf("error clearing tasks: %v", err)
+			return fmt.Errorf("error clearing tasks: %w", err)
 		}
 
-		fmt.Println("All tasks successfully deleted")
+		fmt.Fprintln(out, "All tasks successfully deleted")
 		return nil
 	},
 }
 
 func init() {
+	clearCmd.Flags().BoolVarP(&clearYes, "yes", "y", false, "Delete without asking for confirmation")
 	rootCmd.AddCommand(clearCmd)
 }
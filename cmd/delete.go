@@ -6,7 +6,6 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"log"
 
 	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
 	"github.com/spf13/cobra"
@@ -18,32 +17,47 @@ var deleteCmd = &cobra.Command{
 	Short: "Delete a task from the task list",
 	Long: `The 'delete' command allows you to remove a task from your task list in the system.
 
-You can specify the task ID you want to delete and it will be permanently removed from
-the local JSON file. Make sure to double check the ID before deleting as this action
-cannot be undone.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("delete called")
-	},
+You can specify the task ID you want to delete and it will be removed from the local
+JSON file. Make sure to double check the ID before deleting; if you remove the wrong
+task, 'undo' brings it back.
+
+A task with subtasks is only deleted when --children says what to do with them:
+  refuse    Keep the task and fail (default)
+  cascade   Delete the subtasks too, at any depth
+  reparent  Move the direct subtasks to the parent of the deleted task`,
 }
 
 func init() {
 	rootCmd.AddCommand(deleteCmd)
-	var taskID string
-	deleteCmd.Flags().StringVarP(&taskID, "id", "i", "", "Task ID to delete")
+	var taskID, children string
+	deleteCmd.Flags().StringVarP(&taskID, "id", "i", "", "Task ID or unique ID prefix to delete")
+	deleteCmd.Flags().StringVar(&children, "children", string(task.DeleteRefuse), "What to do with subtasks: refuse, cascade or reparent")
 	deleteCmd.MarkFlagRequired("id")
 	deleteCmd.Flags().SortFlags = false
 
-	deleteCmd.Run = func(cmd *cobra.Command, args []string) {
-		storage, err := task.NewTaskStorage("tasks.json")
+	deleteCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		storage, err := newStorage()
+		if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		}
+		defer storage.Close()
+
+		policy, err := task.ParseDeletePolicy(children)
+		if err != nil {
+			return err
+		}
+
+		id, err := storage.ResolveID(taskID)
 		if err != nil {
-			log.Fatalf("Error initializing storage: %v", err)
+			return err
 		}
 
-		if err := storage.DeleteTask(context.Background(), taskID); err != nil {
-			log.Fatalf("Error deleting task: %v", err)
+		if err := storage.DeleteTaskWithPolicy(context.Background(), id, policy); err != nil {
+			return fmt.Errorf("error deleting task: %w", err)
 		}
 
-		fmt.Printf("Task with ID %s deleted successfully\n", taskID)
+		fmt.Fprintf(cmd.OutOrStdout(), "Task with ID %s deleted successfully\n", id)
+		return nil
 	}
 
 }
@@ -43,3 +43,33 @@ func TestDeleteCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteCommand_Children(t *testing.T) {
+	storage, err := newStorage()
+	assert.NoError(t, err)
+	parent, err := storage.AddTask("Parent", "")
+	assert.NoError(t, err)
+	child, err := storage.AddTask("Child", "", task.WithParent(parent.ID))
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		rootCmd.SetArgs(nil)
+		deleteCmd.Flags().Set("children", string(task.DeleteRefuse))
+	})
+
+	rootCmd.SetArgs([]string{"delete", "--id", parent.ID})
+	err = rootCmd.Execute()
+	assert.ErrorIs(t, err, task.ErrHasChildren)
+	assert.Equal(t, exitValidation, exitCode(err))
+
+	rootCmd.SetArgs([]string{"delete", "--id", parent.ID, "--children", "orphan"})
+	assert.ErrorIs(t, rootCmd.Execute(), task.ErrValidation)
+
+	rootCmd.SetArgs([]string{"delete", "--id", parent.ID, "--children", "cascade"})
+	assert.NoError(t, rootCmd.Execute())
+
+	storage, err = newStorage()
+	assert.NoError(t, err)
+	defer storage.Close()
+	_, err = storage.GetTask(child.ID)
+	assert.ErrorIs(t, err, task.ErrTaskNotFound)
+}
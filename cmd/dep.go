@@ -0,0 +1,75 @@
+/*
+Copyright © 2025 Eddy Nio
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+	"github.com/spf13/cobra"
+)
+
+// depCmd represents the dep command
+var depCmd = &cobra.Command{
+	Use:   "dep",
+	Short: "Add or remove dependencies between tasks",
+	Long: `The 'dep' command manages task dependencies: a task depending on other
+tasks is blocked until all of them are done.
+
+Blocked tasks are flagged by 'list' and left out of 'next'. A dependency that
+would make a task wait on itself, directly or through other tasks, is refused.
+Every change can be reverted with 'undo'.
+
+Examples:
+  task-tracker dep add 3 1 2     # Task 3 can't start until tasks 1 and 2 are done
+  task-tracker dep remove 3 2    # Task 3 no longer waits on task 2`,
+}
+
+var depAddCmd = &cobra.Command{
+	Use:   "add <id> <depends-on>...",
+	Short: "Make a task depend on other tasks",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return changeDependencies(cmd, args, (*task.TaskStorage).AddDependencies)
+	},
+}
+
+var depRemoveCmd = &cobra.Command{
+	Use:   "remove <id> <depends-on>...",
+	Short: "Remove dependencies of a task",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return changeDependencies(cmd, args, (*task.TaskStorage).RemoveDependencies)
+	},
+}
+
+// changeDependencies resolves the task IDs in args, the dependent task first,
+// and applies change to them
+func changeDependencies(cmd *cobra.Command, args []string, change func(*task.TaskStorage, string, ...string) (*task.Task, error)) error {
+	storage, err := newStorage()
+	if err != nil {
+		return fmt.Errorf("error initializing storage: %w", err)
+	}
+	defer storage.Close()
+
+	ids := make([]string, len(args))
+	for i, arg := range args {
+		if ids[i], err = storage.ResolveID(arg); err != nil {
+			return err
+		}
+	}
+
+	updated, err := change(storage, ids[0], ids[1:]...)
+	if err != nil {
+		return fmt.Errorf("error updating dependencies: %w", err)
+	}
+
+	notice(cmd, "Dependencies updated successfully:\n")
+	return printTask(cmd, *updated, storage)
+}
+
+func init() {
+	depCmd.AddCommand(depAddCmd, depRemoveCmd)
+	rootCmd.AddCommand(depCmd)
+}
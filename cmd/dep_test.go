@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/render"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDepAndNextCommands(t *testing.T) {
+	storage, err := newStorage()
+	assert.NoError(t, err)
+	ctx := context.Background()
+	first, err := storage.AddTask("First", "", task.WithPriority(task.PriorityLow))
+	assert.NoError(t, err)
+	second, err := storage.AddTask("Second", "", task.WithPriority(task.PriorityUrgent))
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		storage.DeleteTask(ctx, second.ID)
+		storage.DeleteTask(ctx, first.ID)
+		rootCmd.SetArgs(nil)
+		nextCmd.SetOut(nil)
+		outputFormat = string(render.FormatTable)
+	})
+
+	rootCmd.SetArgs([]string{"dep", "add", second.ID, first.ID, "-o", "json"})
+	assert.NoError(t, rootCmd.Execute())
+
+	rootCmd.SetArgs([]string{"dep", "add", first.ID, second.ID})
+	assert.ErrorIs(t, rootCmd.Execute(), task.ErrValidation)
+
+	buf := new(bytes.Buffer)
+	nextCmd.SetOut(buf)
+	rootCmd.SetArgs([]string{"next", "-n", "0", "-o", "template={{.ID}}"})
+	assert.NoError(t, rootCmd.Execute())
+	ids := strings.Fields(buf.String())
+	assert.Contains(t, ids, first.ID)
+	assert.NotContains(t, ids, second.ID, "a blocked task must not be suggested")
+
+	rootCmd.SetArgs([]string{"dep", "remove", second.ID, first.ID, "-o", "json"})
+	assert.NoError(t, rootCmd.Execute())
+	buf.Reset()
+	rootCmd.SetArgs([]string{"next", "-n", "0", "-o", "template={{.ID}}"})
+	assert.NoError(t, rootCmd.Execute())
+	assert.Contains(t, strings.Fields(buf.String()), second.ID)
+}
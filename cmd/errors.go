@@ -0,0 +1,41 @@
+/*
+Copyright © 2025 Eddy Nio
+*/
+package cmd
+
+import (
+	"errors"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+)
+
+// Exit codes returned by the CLI so scripts can tell failures apart
+const (
+	exitOK         = 0
+	exitError      = 1 // Unexpected or unclassified error
+	exitValidation = 2 // Invalid input: bad field value, nothing to update, ambiguous ID, task with subtasks, forbidden status change
+	exitNotFound   = 3 // The referenced task or backup doesn't exist
+	exitConflict   = 4 // Conflicting state: locked tasks file, journal conflict, timer already running or stopped
+	exitCorrupt    = 5 // The tasks file is corrupt and needs 'repair'
+)
+
+// exitCode maps an error returned by a command to the process exit code
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, task.ErrValidation), errors.Is(err, task.ErrNoUpdatesProvided),
+		errors.Is(err, task.ErrAmbiguousID), errors.Is(err, task.ErrInvalidTaskID), errors.Is(err, task.ErrHasChildren),
+		errors.Is(err, task.ErrTransitionNotAllowed):
+		return exitValidation
+	case errors.Is(err, task.ErrTaskNotFound), errors.Is(err, task.ErrBackupNotFound):
+		return exitNotFound
+	case errors.Is(err, task.ErrStorageLocked), errors.Is(err, task.ErrJournalConflict),
+		errors.Is(err, task.ErrTimerRunning), errors.Is(err, task.ErrNoTimerRunning):
+		return exitConflict
+	case errors.Is(err, task.ErrCorruptStorage):
+		return exitCorrupt
+	default:
+		return exitError
+	}
+}
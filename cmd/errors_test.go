@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"No error", nil, exitOK},
+		{"Validation", fmt.Errorf("error updating task: %w", &task.ValidationError{Field: "title", Message: "too long"}), exitValidation},
+		{"Nothing to update", task.ErrNoUpdatesProvided, exitValidation},
+		{"Task not found", fmt.Errorf("error deleting task: %w", task.ErrTaskNotFound), exitNotFound},
+		{"Locked", fmt.Errorf("failed to save: %w", task.ErrStorageLocked), exitConflict},
+		{"Corrupt", &task.CorruptFileError{Path: "tasks.json", Err: errors.New("bad json")}, exitCorrupt},
+		{"Other", errors.New("boom"), exitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, exitCode(tt.err))
+		})
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright © 2025 Eddy Nio
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+	"github.com/spf13/cobra"
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the change history of a task",
+	Long: `The 'history' command shows every change made to a task, oldest first.
+
+Each event lists when it happened, who made it (task.actor in the config, or your
+system user name), the field that changed and its old and new value.
+
+Examples:
+  task-tracker history --id 1a2b3c4d
+  task-tracker history --id 1a2b3c4d --json   # Machine readable output`,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	var taskID string
+	var asJSON bool
+	historyCmd.Flags().StringVarP(&taskID, "id", "i", "", "Task ID or unique ID prefix to show the history of")
+	historyCmd.Flags().BoolVar(&asJSON, "json", false, "Print the history as JSON")
+	historyCmd.MarkFlagRequired("id")
+	historyCmd.Flags().SortFlags = false
+
+	historyCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		storage, err := newStorage()
+		if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		}
+		defer storage.Close()
+
+		id, err := storage.ResolveID(taskID)
+		if err != nil {
+			return err
+		}
+
+		events, err := storage.TaskHistory(id)
+		if err != nil {
+			return fmt.Errorf("error reading history: %w", err)
+		}
+
+		out := cmd.OutOrStdout()
+		if asJSON {
+			enc := json.NewEncoder(out)
+			enc.SetIndent("", "  ")
+			return enc.Encode(events)
+		}
+
+		for _, e := range events {
+			actor := e.Actor
+			if actor == "" {
+				actor = "-"
+			}
+			fmt.Fprintf(out, "%s  %-10s %s\n", e.At.Local().Format(time.RFC3339), actor, describeChange(e))
+		}
+		return nil
+	}
+}
+
+// describeChange renders a history event as a short sentence
+func describeChange(c task.Change) string {
+	if c.Field == task.FieldCreated {
+		return fmt.Sprintf("created %q", c.New)
+	}
+	return fmt.Sprintf("%s: %q -> %q", c.Field, c.Old, c.New)
+}
@@ -5,7 +5,8 @@ package cmd
 
 import (
 	"fmt"
-	"log"
+	"strings"
+	"time"
 
 	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
 	"github.com/spf13/cobra"
@@ -17,55 +18,202 @@ var listCmd = &cobra.Command{
 	Short: "List and filter tasks in your task list",
 	Long: `The 'list' command displays tasks from your task list with powerful filtering options.
 
+By default tasks are shown as a table fitted to the terminal width; use the global
+--output flag for the wide table (all columns), json, yaml, csv or a Go template.
+
 Each task entry shows:
   • ID: Unique identifier for the task
   • Title: Brief task name
   • Description: Detailed task information
-  • Status: Current state (TODO, IN_PROGRESS, DONE)
+  • Status: Current state (TODO, IN_PROGRESS, DONE), flagged (BLOCKED) while the
+    task depends on unfinished tasks
+  • Priority: Importance (LOW, MEDIUM, HIGH, URGENT)
+  • Due: Due date, if any; overdue tasks are highlighted
+  • Time: Time spent on the task and its estimate, e.g. 1h30m/4h, marked with *
+    while its timer runs; the wide format adds the remaining estimate
+  • Tags: Labels attached to the task
+  • Progress: For tasks with subtasks, how many of them are done, e.g. (3/5 done)
   • Created At: Task creation timestamp
   • Updated At: Last modification timestamp
 
 Available Flags:
   -s, --status string   Filter tasks by their current status:
-                       • TODO - Show only pending tasks
-                       • IN_PROGRESS - Show tasks being worked on
-                       • DONE - Show completed tasks
+                       • TODO (todo, t) - Show only pending tasks
+                       • IN_PROGRESS (in_progress, ip, p) - Show tasks being worked on
+                       • DONE (done, d) - Show completed tasks
+                       Several statuses can be given separated by commas.
+                       Statuses added by the configured workflow are accepted too.
                        If omitted, shows all tasks regardless of status
+      --not-status string
+                       Hide tasks with the given statuses (comma separated)
+  -p, --priority string Filter tasks by priority (low/l, medium/med/m, high/h, urgent/u).
+                       Several priorities can be given separated by commas.
+      --overdue         Show only tasks past their due date that are not done
+      --blocked         Show only tasks waiting on unfinished dependencies
+      --due-before date Show only tasks due before the given date
+      --due-after date  Show only tasks due after the given date
+                       Dates use the configured date format, YYYY-MM-DD or a
+                       phrase like "tomorrow", "next friday" or "in 3 days".
+      --tag string      Show only tasks carrying the tag (repeatable). With several
+                       tags a task must carry all of them, or any of them with --any-tag
+  -w, --where string    Filter tasks with a query expression. Conditions are written as
+                       <field><op><value> and combined with and, or, not and parentheses.
+                       Fields: id, title, description, status, priority, tag, created,
+                       updated, due. Operators: : (or =), !=, >, >=, <, <= and ~ (contains).
+                       Quote values containing spaces. due:none matches tasks without
+                       a due date.
+      --tree            Show subtasks indented below their parent task. Subtasks
+                       whose parent is filtered out are shown at the top level.
+
+Tasks are sorted by priority, most important first, and then by age, oldest first.
 
 Examples:
   task list            # Lists all tasks
   task list -s TODO    # Lists only pending tasks
-  task list -s DONE    # Lists only completed tasks`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("list called")
-	},
+  task list -s DONE    # Lists only completed tasks
+  task list -s todo,ip # Lists pending tasks and tasks being worked on
+  task list --not-status done  # Lists every task that is not completed
+  task list -p high,u  # Lists only high and urgent tasks
+  task list --overdue  # Lists tasks that are past their due date
+  task list --blocked  # Lists tasks that can't start yet
+  task list --due-before "next friday"
+  task list --tag backend --tag api            # Tasks tagged both backend and api
+  task list --tag backend --tag api --any-tag  # Tasks tagged backend or api
+  task list --tree      # Lists tasks with their subtasks below them
+  task list -o json     # Lists all tasks as JSON
+  task list -o 'template={{.ID}} {{.Title}} {{join .Tags ","}}'
+  task list -w 'status:todo and (tag:backend or priority>=high) and created>2026-01-01 and title~"deploy"'`,
 }
 
 func init() {
 	rootCmd.AddCommand(listCmd)
-	var status string
-	listCmd.Flags().StringVarP(&status, "status", "s", "", "Filter tasks by status (TODO, IN_PROGRESS, DONE)")
+	var status, notStatus, priority, dueBefore, dueAfter, where string
+	var overdue, blocked, anyTag, asTree bool
+	var tags []string
+	listCmd.Flags().StringVarP(&status, "status", "s", "", "Filter tasks by status (todo/t, in_progress/ip/p, done/d), comma separated")
+	listCmd.Flags().StringVar(&notStatus, "not-status", "", "Exclude tasks with these statuses, comma separated")
+	listCmd.Flags().StringVarP(&priority, "priority", "p", "", "Filter tasks by priority (low, medium, high, urgent)")
+	listCmd.Flags().BoolVar(&overdue, "overdue", false, "Show only overdue tasks")
+	listCmd.Flags().BoolVar(&blocked, "blocked", false, "Show only tasks waiting on unfinished dependencies")
+	listCmd.Flags().StringVar(&dueBefore, "due-before", "", "Show only tasks due before this date")
+	listCmd.Flags().StringVar(&dueAfter, "due-after", "", "Show only tasks due after this date")
+	listCmd.Flags().StringArrayVar(&tags, "tag", nil, "Show only tasks with this tag (repeatable)")
+	listCmd.Flags().BoolVar(&anyTag, "any-tag", false, "Match tasks carrying any of the --tag values instead of all")
+	listCmd.Flags().StringVarP(&where, "where", "w", "", "Filter tasks with a query expression (see 'list --help')")
+	listCmd.Flags().BoolVar(&asTree, "tree", false, "Show subtasks indented below their parent")
 	listCmd.Flags().SortFlags = false
 
-	listCmd.Run = func(cmd *cobra.Command, args []string) {
-		storage, err := task.NewTaskStorage("tasks.json")
+	listCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		storage, err := newStorage()
 		if err != nil {
-			log.Fatalf("Error initializing storage: %v", err)
+			return fmt.Errorf("error initializing storage: %w", err)
 		}
+		defer storage.Close()
 
-		var tasks []task.Task
+		tasks, err := storage.Query(where)
+		if err != nil {
+			return err
+		}
 		if status != "" {
-			tasks = storage.ListTasksByStatus(task.Status(status))
-		} else {
-			tasks = storage.ListTasks()
+			statuses, err := parseStatuses(status, storage.Workflow())
+			if err != nil {
+				return err
+			}
+			tasks = filterTasks(tasks, func(t task.Task) bool { return statuses[t.Status] })
+		}
+		if notStatus != "" {
+			excluded, err := parseStatuses(notStatus, storage.Workflow())
+			if err != nil {
+				return err
+			}
+			tasks = filterTasks(tasks, func(t task.Task) bool { return !excluded[t.Status] })
+		}
+
+		if priority != "" {
+			priorities, err := parsePriorities(priority)
+			if err != nil {
+				return err
+			}
+			tasks = filterByPriority(tasks, priorities)
 		}
 
-		if len(tasks) == 0 {
-			fmt.Println("No tasks found")
-			return
+		now := time.Now()
+		if overdue {
+			tasks = filterTasks(tasks, func(t task.Task) bool { return t.IsOverdue(now, storage.Workflow()) })
+		}
+		if blocked {
+			blockedBy := storage.BlockedBy()
+			tasks = filterTasks(tasks, func(t task.Task) bool { return len(blockedBy[t.ID]) > 0 })
+		}
+		if dueBefore != "" {
+			before, err := parseDate(dueBefore)
+			if err != nil {
+				return err
+			}
+			tasks = filterTasks(tasks, func(t task.Task) bool { return t.DueAt != nil && t.DueAt.Before(before) })
+		}
+		if dueAfter != "" {
+			after, err := parseDate(dueAfter)
+			if err != nil {
+				return err
+			}
+			tasks = filterTasks(tasks, func(t task.Task) bool { return t.DueAt != nil && t.DueAt.After(after) })
+		}
+		if len(tags) > 0 {
+			for i, tag := range tags {
+				if tags[i], err = task.NormalizeTag(tag); err != nil {
+					return err
+				}
+			}
+			tasks = filterTasks(tasks, func(t task.Task) bool { return t.HasTags(tags, anyTag) })
 		}
 
-		storage.PrintTasks()
+		task.SortTasks(tasks)
+		if asTree {
+			return printTree(cmd, tasks, storage)
+		}
+		return printTasks(cmd, tasks, storage)
 	}
+}
 
+// parseStatuses validates a comma separated list of status names or aliases
+// of workflow w
+func parseStatuses(list string, w *task.Workflow) (map[task.Status]bool, error) {
+	statuses := make(map[task.Status]bool)
+	for _, name := range strings.Split(list, ",") {
+		s, err := w.ValidateStatus(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		statuses[s] = true
+	}
+	return statuses, nil
+}
+
+// parsePriorities validates a comma separated list of priority names or aliases
+func parsePriorities(list string) (map[task.Priority]bool, error) {
+	priorities := make(map[task.Priority]bool)
+	for _, name := range strings.Split(list, ",") {
+		p, err := task.ValidatePriority(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		priorities[p] = true
+	}
+	return priorities, nil
+}
+
+func filterByPriority(tasks []task.Task, priorities map[task.Priority]bool) []task.Task {
+	return filterTasks(tasks, func(t task.Task) bool { return priorities[t.Priority] })
+}
+
+// filterTasks returns the tasks for which keep returns true
+func filterTasks(tasks []task.Task, keep func(task.Task) bool) []task.Task {
+	var filtered []task.Task
+	for _, t := range tasks {
+		if keep(t) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
 }
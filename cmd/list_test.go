@@ -2,9 +2,13 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/Eddy-Nio/task-tracker-cli/internal/render"
 	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
 	"github.com/stretchr/testify/assert"
 )
@@ -57,3 +61,96 @@ func TestListCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestListCommand_OutputFormats(t *testing.T) {
+	storage, err := newStorage()
+	assert.NoError(t, err)
+	added, err := storage.AddTask("Rendered", "Task to render")
+	assert.NoError(t, err)
+	t.Cleanup(func() { storage.DeleteTask(context.Background(), added.ID) })
+
+	buf := new(bytes.Buffer)
+	listCmd.SetOut(buf)
+	t.Cleanup(func() {
+		listCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+		outputFormat = string(render.FormatTable)
+	})
+
+	rootCmd.SetArgs([]string{"list", "-o", "json"})
+	assert.NoError(t, rootCmd.Execute())
+	var tasks []task.Task
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &tasks))
+	assert.Contains(t, buf.String(), added.ID)
+
+	buf.Reset()
+	rootCmd.SetArgs([]string{"list", "-o", "template={{.ID}}:{{.Title}}"})
+	assert.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), added.ID+":Rendered\n")
+
+	rootCmd.SetArgs([]string{"list", "-o", "xml"})
+	err = rootCmd.Execute()
+	assert.ErrorIs(t, err, task.ErrValidation)
+}
+
+func TestListCommand_StatusFilters(t *testing.T) {
+	storage, err := newStorage()
+	assert.NoError(t, err)
+	ctx := context.Background()
+	ids := map[task.Status]string{}
+	for _, status := range []task.Status{task.StatusTodo, task.StatusInProgress, task.StatusDone} {
+		added, err := storage.AddTask("Status "+string(status), "")
+		assert.NoError(t, err)
+		_, err = storage.UpdateTask(ctx, added.ID, task.TaskPatch{Status: &status})
+		assert.NoError(t, err)
+		ids[status] = added.ID
+		t.Cleanup(func() { storage.DeleteTask(ctx, added.ID) })
+	}
+
+	buf := new(bytes.Buffer)
+	listCmd.SetOut(buf)
+	t.Cleanup(func() {
+		listCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+		outputFormat = string(render.FormatTable)
+		listCmd.Flags().Set("status", "")
+		listCmd.Flags().Set("not-status", "")
+	})
+
+	tests := []struct {
+		name string
+		args []string
+		want []task.Status
+	}{
+		{"Alias", []string{"-s", "t"}, []task.Status{task.StatusTodo}},
+		{"Lower-case name", []string{"-s", "todo"}, []task.Status{task.StatusTodo}},
+		{"Several statuses", []string{"-s", "todo,ip"}, []task.Status{task.StatusTodo, task.StatusInProgress}},
+		{"Negation", []string{"-s", "", "--not-status", "done"}, []task.Status{task.StatusTodo, task.StatusInProgress}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			listCmd.Flags().Set("not-status", "")
+			rootCmd.SetArgs(append([]string{"list", "-o", "template={{.ID}}"}, tt.args...))
+			assert.NoError(t, rootCmd.Execute())
+
+			var want []string
+			for _, status := range tt.want {
+				want = append(want, ids[status])
+			}
+			var got []string
+			for _, id := range strings.Fields(buf.String()) {
+				for _, own := range ids {
+					if id == own {
+						got = append(got, id)
+					}
+				}
+			}
+			assert.ElementsMatch(t, want, got)
+		})
+	}
+
+	rootCmd.SetArgs([]string{"list", "-s", "blocked"})
+	assert.ErrorIs(t, rootCmd.Execute(), task.ErrValidation)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright © 2025 Eddy Nio
+*/
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// migrateIDsCmd represents the migrate-ids command
+var migrateIDsCmd = &cobra.Command{
+	Use:   "migrate-ids",
+	Short: "Give tasks with old 8 character IDs an ID following task.idStrategy",
+	Long: `The 'migrate-ids' command replaces the 8 character IDs generated by earlier
+versions with IDs following the configured task.idStrategy (sequential by default),
+oldest task first. The short strategy cannot be migrated to, as its IDs have the
+old format.
+
+The old ID is kept as an alias of the task, so scripts and notes referring to it
+keep working with every command taking an ID. The migration can be reverted with
+'undo'.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storage, err := newStorage()
+		if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		}
+		defer storage.Close()
+
+		migrated, err := storage.MigrateIDs()
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		if len(migrated) == 0 {
+			fmt.Fprintln(out, "No task IDs to migrate")
+			return nil
+		}
+
+		olds := make([]string, 0, len(migrated))
+		for old := range migrated {
+			olds = append(olds, old)
+		}
+		sort.Strings(olds)
+		for _, old := range olds {
+			fmt.Fprintf(out, "%s -> %s\n", old, migrated[old])
+		}
+		fmt.Fprintf(out, "Migrated %d task ID(s)\n", len(migrated))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateIDsCmd)
+}
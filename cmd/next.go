@@ -0,0 +1,49 @@
+/*
+Copyright © 2025 Eddy Nio
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var nextLimit int
+
+// nextCmd represents the next command
+var nextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Suggest the tasks to work on next",
+	Long: `The 'next' command lists the TODO tasks that are not blocked by unfinished
+dependencies, in the order they should be worked on.
+
+Tasks are ordered by priority, where a task counts as important as the most
+important task waiting on it, directly or through other tasks: a low priority
+task blocking an urgent one comes before a high priority task. Ties go to the
+task's own priority and then to the oldest task.
+
+Examples:
+  task-tracker next
+  task-tracker next -n 1
+  task-tracker next -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storage, err := newStorage()
+		if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		}
+		defer storage.Close()
+
+		tasks := storage.NextTasks()
+		if nextLimit > 0 && len(tasks) > nextLimit {
+			tasks = tasks[:nextLimit]
+		}
+		return printTasks(cmd, tasks, storage)
+	},
+}
+
+func init() {
+	nextCmd.Flags().IntVarP(&nextLimit, "limit", "n", 5, "Maximum number of tasks, 0 for all")
+	rootCmd.AddCommand(nextCmd)
+}
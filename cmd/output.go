@@ -0,0 +1,86 @@
+/*
+Copyright © 2025 Eddy Nio
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/config"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/render"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+	"github.com/spf13/cobra"
+)
+
+// outputFormat is the value of the persistent --output flag
+var outputFormat string
+
+// newRenderer returns the renderer selected with --output for the output of
+// cmd. When storage is not nil, the progress of parent tasks and the blocked
+// state of tasks are taken from it.
+func newRenderer(cmd *cobra.Command, storage *task.TaskStorage) (render.Renderer, error) {
+	out := cmd.OutOrStdout()
+	dateFormat := config.DefaultConfig.Task.DateFormat
+	if cfg != nil {
+		dateFormat = cfg.Task.DateFormat
+	}
+
+	opts := render.Options{
+		Width:      render.TerminalWidth(out),
+		Color:      render.IsTerminal(out),
+		DateFormat: dateFormat,
+		Now:        time.Now(),
+	}
+	if storage != nil {
+		opts.Progress = storage.Progress()
+		opts.Blocked = storage.BlockedBy()
+		opts.Workflow = storage.Workflow()
+	}
+	return render.New(outputFormat, opts)
+}
+
+// printTasks writes tasks in the selected output format
+func printTasks(cmd *cobra.Command, tasks []task.Task, storage *task.TaskStorage) error {
+	r, err := newRenderer(cmd, storage)
+	if err != nil {
+		return err
+	}
+	return r.Tasks(cmd.OutOrStdout(), tasks)
+}
+
+// printTree writes tasks with their subtasks below them. Formats that cannot
+// draw the tree get the tasks in tree order.
+func printTree(cmd *cobra.Command, tasks []task.Task, storage *task.TaskStorage) error {
+	r, err := newRenderer(cmd, storage)
+	if err != nil {
+		return err
+	}
+	if tr, ok := r.(render.TreeRenderer); ok {
+		return tr.Tree(cmd.OutOrStdout(), tasks)
+	}
+	return r.Tasks(cmd.OutOrStdout(), render.TreeOrder(tasks))
+}
+
+// printTask writes a single task in the selected output format
+func printTask(cmd *cobra.Command, t task.Task, storage *task.TaskStorage) error {
+	r, err := newRenderer(cmd, storage)
+	if err != nil {
+		return err
+	}
+	return r.Task(cmd.OutOrStdout(), t)
+}
+
+// notice writes a message meant for people. It is left out of the
+// machine readable formats so their output can be parsed as is.
+func notice(cmd *cobra.Command, format string, args ...any) {
+	if render.IsHuman(outputFormat) {
+		fmt.Fprintf(cmd.OutOrStdout(), format, args...)
+	}
+}
+
+// warnBlocked warns that the task with the given ID waits on unfinished tasks
+func warnBlocked(cmd *cobra.Command, id string, blockers []string) {
+	fmt.Fprintf(cmd.ErrOrStderr(), "Warning: task %s is blocked by unfinished tasks: %s\n", id, strings.Join(blockers, ", "))
+}
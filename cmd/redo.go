@@ -0,0 +1,42 @@
+/*
+Copyright © 2025 Eddy Nio
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// redoCmd represents the redo command
+var redoCmd = &cobra.Command{
+	Use:   "redo",
+	Short: "Re-apply the last undone change",
+	Long: `The 'redo' command re-applies the operation most recently reverted by 'undo'.
+
+See 'task-tracker undo --help' for how the journal works.`,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	rootCmd.AddCommand(redoCmd)
+	var force bool
+	redoCmd.Flags().BoolVarP(&force, "force", "f", false, "Redo even if the tasks changed since the undo")
+
+	redoCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		storage, err := newStorage()
+		if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		}
+		defer storage.Close()
+
+		op, err := storage.Redo(force)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Redid #%d %s: %s\n", op.Seq, op.Kind, op.Summary())
+		return nil
+	}
+}
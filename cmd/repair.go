@@ -0,0 +1,108 @@
+/*
+Copyright © 2025 Eddy Nio
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+	"github.com/spf13/cobra"
+)
+
+// repairCmd represents the repair command
+var repairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Recover tasks from a corrupt tasks file",
+	Long: `The 'repair' command salvages tasks from a tasks file that could not be read.
+
+When the tasks file is corrupt it is moved to the backup directory with a
+'.corrupt-<timestamp>' suffix and the other commands refuse to run until it is
+repaired (or storage.recoverCorrupt is set in the config). 'repair' reads the most
+recent quarantined file (or the file given with --from), recovers every task
+object that can still be decoded on its own and adds the ones that are missing to
+the current tasks file, which the other commands then use again. A dry run leaves
+the tasks file refused.
+
+Examples:
+  task-tracker repair                                  # Recover from the latest quarantined file
+  task-tracker repair --from backups/tasks.json.corrupt-20250101T120000Z
+  task-tracker repair --dry-run                        # Only show what would be recovered`,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	rootCmd.AddCommand(repairCmd)
+	var from string
+	var dryRun bool
+	repairCmd.Flags().StringVarP(&from, "from", "f", "", "Damaged file to recover tasks from (default: latest quarantined file)")
+	repairCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the recoverable tasks without saving them")
+	repairCmd.Flags().SortFlags = false
+
+	repairCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+
+		// A corrupt tasks file is quarantined by opening the storage, which
+		// keeps failing until the quarantine is resolved below
+		storage, err := newStorage()
+		var corrupt *task.CorruptFileError
+		if errors.As(err, &corrupt) {
+			fmt.Fprintf(out, "Tasks file %s is corrupt and was moved to %s\n", corrupt.Path, corrupt.QuarantinePath)
+		} else if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		} else {
+			defer storage.Close()
+		}
+
+		source := from
+		if source == "" {
+			files, err := task.QuarantinedFiles(cfg.Storage.FilePath, cfg.Storage.BackupDir)
+			if err != nil {
+				return fmt.Errorf("error looking for quarantined files: %v", err)
+			}
+			if len(files) == 0 {
+				return fmt.Errorf("no quarantined tasks file found; use --from to choose the file to repair")
+			}
+			source = files[0]
+		}
+
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %v", source, err)
+		}
+
+		workflow, err := task.NewWorkflow(cfg.Task.Workflow)
+		if err != nil {
+			return err
+		}
+		salvaged, skipped := task.SalvageTasks(data, workflow)
+		fmt.Fprintf(out, "Found %d recoverable task(s) in %s (%d unreadable object(s) skipped)\n", len(salvaged), source, skipped)
+
+		if dryRun {
+			for _, t := range salvaged {
+				fmt.Fprintf(out, "  %s  %s\n", t.ID, t.Title)
+			}
+			return nil
+		}
+
+		if storage == nil {
+			if err := task.ResolveQuarantine(cfg.Storage.FilePath); err != nil {
+				return err
+			}
+			if storage, err = newStorage(); err != nil {
+				return fmt.Errorf("error initializing storage: %w", err)
+			}
+			defer storage.Close()
+		}
+
+		added, err := storage.ImportTasks(salvaged)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Recovered %d task(s); %d were already present\n", added, len(salvaged)-added)
+		return nil
+	}
+}
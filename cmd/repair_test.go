@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepairCommand_CorruptFileStaysRefused(t *testing.T) {
+	storage, err := newStorage()
+	assert.NoError(t, err)
+	storage.Close()
+
+	path := cfg.Storage.FilePath
+	original, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		task.ResolveQuarantine(path)
+		os.WriteFile(path, original, 0644)
+	})
+
+	damaged := `[{"id":"r1","title":"Salvage me","status":"TODO","created_at":"2026-03-01T09:00:00Z","updated_at":"2026-03-01T09:00:00Z"},]`
+	assert.NoError(t, os.WriteFile(path, []byte(damaged), 0644))
+
+	buf := new(bytes.Buffer)
+	listCmd.SetOut(buf)
+	repairCmd.SetOut(buf)
+	t.Cleanup(func() {
+		listCmd.SetOut(nil)
+		repairCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+		repairCmd.Flags().Set("dry-run", "false")
+	})
+
+	// The first command quarantines the file, the next ones keep refusing
+	for i := 0; i < 2; i++ {
+		rootCmd.SetArgs([]string{"list"})
+		assert.Equal(t, exitCorrupt, exitCode(rootCmd.Execute()), "run %d", i+1)
+	}
+
+	rootCmd.SetArgs([]string{"repair", "--dry-run"})
+	assert.NoError(t, rootCmd.Execute())
+	rootCmd.SetArgs([]string{"list"})
+	assert.Equal(t, exitCorrupt, exitCode(rootCmd.Execute()), "a dry run must not resolve the quarantine")
+
+	rootCmd.SetArgs([]string{"repair", "--dry-run=false"})
+	assert.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), "Recovered 1 task(s)")
+
+	buf.Reset()
+	rootCmd.SetArgs([]string{"list"})
+	assert.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), "Salvage me")
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2025 Eddy Nio
+*/
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/render"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+	"github.com/spf13/cobra"
+)
+
+var reportSince, reportUntil string
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarize throughput and time spent",
+	Long: `The 'report' command summarizes the work done over a range of days, split
+into days or weeks:
+
+  - the tasks created and completed in every period
+  - the average lead time, from creation to completion
+  - the average cycle time, from the move to IN_PROGRESS (or the first timer)
+    to completion
+  - the time spent per tag, from the timers and logged work
+
+Tasks count as completed when they reach a done status. The report is a text
+table, or JSON or YAML with --output.`,
+	Args: cobra.NoArgs,
+}
+
+// reportDailyCmd represents the report daily command
+var reportDailyCmd = &cobra.Command{
+	Use:   "daily",
+	Short: "Report per day, over the last 7 days by default",
+	Example: `  task-tracker report daily
+  task-tracker report daily --since 2025-03-01 --until 2025-03-07
+  task-tracker report daily -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runReport(cmd, task.PeriodDay)
+	},
+}
+
+// reportWeeklyCmd represents the report weekly command
+var reportWeeklyCmd = &cobra.Command{
+	Use:   "weekly",
+	Short: "Report per week, over the last 4 weeks by default",
+	Example: `  task-tracker report weekly
+  task-tracker report weekly --since 2025-01-06`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runReport(cmd, task.PeriodWeek)
+	},
+}
+
+func init() {
+	reportCmd.PersistentFlags().StringVar(&reportSince, "since", "", "First day of the report, e.g. 2025-03-01")
+	reportCmd.PersistentFlags().StringVar(&reportUntil, "until", "", "Last day of the report (default now)")
+	reportCmd.AddCommand(reportDailyCmd, reportWeeklyCmd)
+	rootCmd.AddCommand(reportCmd)
+}
+
+func runReport(cmd *cobra.Command, period task.Period) error {
+	now := time.Now()
+	until := now
+	if reportUntil != "" {
+		parsed, err := parseDate(reportUntil)
+		if err != nil {
+			return err
+		}
+		until = parsed
+	}
+
+	// The default range covers the current period and the previous ones
+	since := task.PeriodStart(period, until).AddDate(0, 0, -6)
+	if period == task.PeriodWeek {
+		since = task.PeriodStart(period, until).AddDate(0, 0, -3*7)
+	}
+	if reportSince != "" {
+		parsed, err := parseDate(reportSince)
+		if err != nil {
+			return err
+		}
+		since = task.PeriodStart(task.PeriodDay, parsed)
+	}
+	if since.After(until) {
+		return &task.ValidationError{Field: "since", Message: "--since must not be after --until"}
+	}
+
+	storage, err := newStorage()
+	if err != nil {
+		return fmt.Errorf("error initializing storage: %w", err)
+	}
+	defer storage.Close()
+
+	return render.Report(cmd.OutOrStdout(), outputFormat, storage.Report(period, since, until))
+}
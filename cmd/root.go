@@ -4,11 +4,21 @@ Copyright © 2025 Eddy Nio
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"time"
 
+	"github.com/Eddy-Nio/task-tracker-cli/config"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/render"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
 	"github.com/spf13/cobra"
 )
 
+var (
+	cfgFile string         // Value of the --config flag
+	cfg     *config.Config // Configuration loaded before any command runs
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "task-tracker-cli",
 	Short: "A CLI tool to manage and track your tasks",
@@ -21,16 +31,67 @@ With this tool you can:
 - Delete tasks when completed
 - Clear all tasks when needed
 
-All tasks are stored locally in a JSON file for easy access and persistence.`,
+All tasks are stored locally in a JSON file for easy access and persistence.
+
+Configuration is read from --config when given, otherwise from
+$XDG_CONFIG_HOME/task-tracker/config.yaml and ./config.yaml (in that order).
+Any setting can be overridden with TASK_TRACKER_<SECTION>_<KEY> environment
+variables, e.g. TASK_TRACKER_STORAGE_FILEPATH.
+
+Commands showing tasks honor --output (-o): table (default), wide, json, yaml,
+csv or template=<go-template>, e.g. -o 'template={{.ID}} {{.Title}}'.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Flags and arguments are valid at this point; don't print usage for runtime errors
+		cmd.SilenceUsage = true
+		if err := initConfig(); err != nil {
+			return err
+		}
+		// Reject a bad --output before any change is made
+		_, err := newRenderer(cmd, nil)
+		return err
+	},
 }
 
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default $XDG_CONFIG_HOME/task-tracker/config.yaml, then ./config.yaml)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", string(render.FormatTable),
+		"output format: table, wide, json, yaml, csv or template=<go-template>")
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
+
+// initConfig loads the configuration honoring the --config flag
+func initConfig() error {
+	loaded, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+	cfg = loaded
+	return nil
+}
+
+// newStorage opens the task storage described by the loaded configuration
+func newStorage() (*task.TaskStorage, error) {
+	if cfg == nil {
+		if err := initConfig(); err != nil {
+			return nil, err
+		}
+	}
+	return task.NewTaskStorageWithConfig(cfg)
+}
+
+// parseDate parses a date flag using the configured date format or a phrase
+// such as "tomorrow" or "in 3 days"
+func parseDate(s string) (time.Time, error) {
+	layout := config.DefaultConfig.Task.DateFormat
+	if cfg != nil {
+		layout = cfg.Task.DateFormat
+	}
+	return task.ParseDate(s, layout, time.Now())
+}
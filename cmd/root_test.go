@@ -2,9 +2,27 @@ package cmd
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 )
 
+// TestMain points the commands at a throwaway config home and tasks file so
+// the tests never read the user's configuration or write into the package dir.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "task-tracker-cmd-*")
+	if err != nil {
+		panic(err)
+	}
+
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	os.Setenv("TASK_TRACKER_STORAGE_FILEPATH", filepath.Join(dir, "tasks.json"))
+	os.Setenv("TASK_TRACKER_STORAGE_BACKUPDIR", filepath.Join(dir, "backups"))
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func TestExecute(t *testing.T) {
 	if err := rootCmd.Execute(); err != nil {
 		t.Errorf("Error ejecutando rootCmd: %v", err)
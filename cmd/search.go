@@ -0,0 +1,83 @@
+/*
+Copyright © 2025 Eddy Nio
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/render"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+	"github.com/spf13/cobra"
+)
+
+var searchLimit int
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search <terms>...",
+	Short: "Search task titles and descriptions",
+	Long: `The 'search' command finds tasks whose title or description contain every
+search term.
+
+Matching ignores case and accents, and a term also matches words starting with it,
+so "depl" finds "Deployment". Results are ranked by relevance: whole-word matches
+rank above prefix matches and title matches above description matches. Matched
+words are highlighted in the output; other --output formats than table and wide
+print the matching tasks, most relevant first, without highlighting.
+
+Examples:
+  task-tracker search deploy
+  task-tracker search cafe menu     # Also finds "Café menu"
+  task-tracker search -n 5 api`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storage, err := newStorage()
+		if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		}
+		defer storage.Close()
+
+		out := cmd.OutOrStdout()
+		results := storage.Search(strings.Join(args, " "))
+		if searchLimit > 0 && len(results) > searchLimit {
+			results = results[:searchLimit]
+		}
+		if !render.IsHuman(outputFormat) {
+			tasks := make([]task.Task, len(results))
+			for i, r := range results {
+				tasks[i] = r.Task
+			}
+			return printTasks(cmd, tasks, storage)
+		}
+		if len(results) == 0 {
+			fmt.Fprintln(out, "No tasks found")
+			return nil
+		}
+
+		mark := highlighter(out)
+		for _, r := range results {
+			fmt.Fprintf(out, "%s  %-11s  %s\n", r.Task.ID, r.Task.Status, r.Title(mark))
+			if r.Task.Description != "" {
+				fmt.Fprintf(out, "    %s\n", r.Snippet(80, mark))
+			}
+		}
+		return nil
+	},
+}
+
+// highlighter returns a function emphasizing matched text: bold yellow on a
+// terminal, brackets otherwise so the matches stay visible in pipes
+func highlighter(out io.Writer) func(string) string {
+	if render.IsTerminal(out) {
+		return func(s string) string { return "\033[1;33m" + s + "\033[0m" }
+	}
+	return func(s string) string { return "[" + s + "]" }
+}
+
+func init() {
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 20, "Maximum number of results, 0 for all")
+	rootCmd.AddCommand(searchCmd)
+}
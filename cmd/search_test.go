@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/render"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchCommand_OutputFormats(t *testing.T) {
+	storage, err := newStorage()
+	assert.NoError(t, err)
+	added, err := storage.AddTask("Deploy the searchable service", "Roll out the release")
+	assert.NoError(t, err)
+	t.Cleanup(func() { storage.DeleteTask(context.Background(), added.ID) })
+
+	buf := new(bytes.Buffer)
+	searchCmd.SetOut(buf)
+	t.Cleanup(func() {
+		searchCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+		outputFormat = string(render.FormatTable)
+	})
+
+	rootCmd.SetArgs([]string{"search", "searchable"})
+	assert.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), "Deploy the [searchable] service")
+
+	// Machine readable output gets the tasks, without highlighting
+	buf.Reset()
+	rootCmd.SetArgs([]string{"search", "searchable", "-o", "json"})
+	assert.NoError(t, rootCmd.Execute())
+	var tasks []task.Task
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &tasks))
+	if assert.Len(t, tasks, 1) {
+		assert.Equal(t, "Deploy the searchable service", tasks[0].Title)
+	}
+}
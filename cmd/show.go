@@ -0,0 +1,52 @@
+/*
+Copyright © 2025 Eddy Nio
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// showCmd represents the show command
+var showCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show the details of a task",
+	Long: `The 'show' command prints every field of a single task.
+
+Like every command taking a task ID, it accepts a unique prefix of the ID
+(git-style): if only one task ID starts with "1a2", "show 1a2" shows that task.
+When the prefix matches several tasks, the candidates are listed instead.
+
+For a task with subtasks, the number of subtasks done out of all of them,
+at any depth, is shown as well.
+
+Examples:
+  task-tracker show 1a2b3c4d
+  task-tracker show 1a2
+  task-tracker show 1a2 -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storage, err := newStorage()
+		if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		}
+		defer storage.Close()
+
+		id, err := storage.ResolveID(args[0])
+		if err != nil {
+			return err
+		}
+
+		t, err := storage.GetTask(id)
+		if err != nil {
+			return err
+		}
+		return printTask(cmd, t, storage)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShowCommand(t *testing.T) {
+	storage, err := newStorage()
+	assert.NoError(t, err)
+	added, err := storage.AddTask("Shown", "Task to show")
+	assert.NoError(t, err)
+	t.Cleanup(func() { storage.DeleteTask(context.Background(), added.ID) })
+
+	buf := new(bytes.Buffer)
+	showCmd.SetOut(buf)
+	t.Cleanup(func() {
+		showCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+	})
+
+	rootCmd.SetArgs([]string{"show", added.ID})
+	assert.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), "ID: "+added.ID)
+	assert.Contains(t, buf.String(), "Title: Shown")
+
+	rootCmd.SetArgs([]string{"show", "zzzzzzzz"})
+	err = rootCmd.Execute()
+	assert.Equal(t, exitNotFound, exitCode(err))
+}
@@ -0,0 +1,91 @@
+/*
+Copyright © 2025 Eddy Nio
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/render"
+	"github.com/spf13/cobra"
+)
+
+// tagsCmd represents the tags command
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "List the tags in use and rename or merge them",
+	Long: `The 'tags' command lists every tag attached to a task with the number of
+tasks carrying it, in the format selected with --output (table, json, yaml or
+csv).
+
+Use 'tags rename' to rename a tag on every task, or 'tags merge' to fold several
+tags into one. Each of them saves all affected tasks at once and can be reverted
+with 'undo'.
+
+Examples:
+  task-tracker tags
+  task-tracker tags -o json
+  task-tracker tags rename backend server
+  task-tracker tags merge bug bugs defect --into bug`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storage, err := newStorage()
+		if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		}
+		defer storage.Close()
+
+		return render.Tags(cmd.OutOrStdout(), outputFormat, storage.TagCounts())
+	},
+}
+
+var tagsRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a tag on every task, merging it if the new tag already exists",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storage, err := newStorage()
+		if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		}
+		defer storage.Close()
+
+		changed, err := storage.RenameTag(args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Renamed tag %q to %q on %d task(s)\n", args[0], args[1], changed)
+		return nil
+	},
+}
+
+var tagsMergeInto string
+
+var tagsMergeCmd = &cobra.Command{
+	Use:   "merge <tag>... --into <tag>",
+	Short: "Replace several tags with a single one on every task",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storage, err := newStorage()
+		if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		}
+		defer storage.Close()
+
+		changed, err := storage.MergeTags(tagsMergeInto, args...)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Merged %d tag(s) into %q on %d task(s)\n", len(args), tagsMergeInto, changed)
+		return nil
+	},
+}
+
+func init() {
+	tagsMergeCmd.Flags().StringVar(&tagsMergeInto, "into", "", "Tag replacing the merged tags")
+	tagsMergeCmd.MarkFlagRequired("into")
+	tagsCmd.AddCommand(tagsRenameCmd, tagsMergeCmd)
+	rootCmd.AddCommand(tagsCmd)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright © 2025 Eddy Nio
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+	"github.com/spf13/cobra"
+)
+
+// startCmd represents the start command
+var startCmd = &cobra.Command{
+	Use:   "start <id>",
+	Short: "Start the timer of a task",
+	Long: `The 'start' command starts recording work on a task and moves it to
+IN_PROGRESS, or the started status of the configured workflow. Stop the timer
+with 'stop'; the time in between is added to the time spent on the task, shown
+by 'list' and 'show'.
+
+Only one timer runs at a time: stop the running one before starting another.
+
+Examples:
+  task-tracker start 3
+  task-tracker stop`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storage, err := newStorage()
+		if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		}
+		defer storage.Close()
+
+		id, err := storage.ResolveID(args[0])
+		if err != nil {
+			return err
+		}
+		if blockers := storage.BlockedBy()[id]; len(blockers) > 0 {
+			warnBlocked(cmd, id, blockers)
+		}
+
+		started, err := storage.StartTimer(id)
+		if errors.Is(err, task.ErrTimerRunning) {
+			return fmt.Errorf("error starting timer: %w (stop it with 'stop')", err)
+		}
+		if err != nil {
+			return fmt.Errorf("error starting timer: %w", err)
+		}
+
+		notice(cmd, "Timer started:\n")
+		return printTask(cmd, *started, storage)
+	},
+}
+
+// stopCmd represents the stop command
+var stopCmd = &cobra.Command{
+	Use:   "stop [id]",
+	Short: "Stop the running timer",
+	Long: `The 'stop' command stops the timer started with 'start' and adds the
+elapsed time to the time spent on the task. Without an ID it stops whichever
+timer is running.
+
+Marking a task as done stops its timer as well.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storage, err := newStorage()
+		if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		}
+		defer storage.Close()
+
+		var id string
+		if len(args) == 1 {
+			if id, err = storage.ResolveID(args[0]); err != nil {
+				return err
+			}
+		} else {
+			running, ok := storage.RunningTimer()
+			if !ok {
+				return fmt.Errorf("error stopping timer: %w", task.ErrNoTimerRunning)
+			}
+			id = running.ID
+		}
+
+		stopped, err := storage.StopTimer(id)
+		if err != nil {
+			return fmt.Errorf("error stopping timer: %w", err)
+		}
+
+		notice(cmd, "Timer stopped:\n")
+		return printTask(cmd, *stopped, storage)
+	},
+}
+
+// logCmd represents the log command
+var logCmd = &cobra.Command{
+	Use:   "log <id> <duration>",
+	Short: "Log work done on a task",
+	Long: `The 'log' command adds work done without a timer to the time spent on a
+task. The duration is written like 1h30m, 45m or 2h.
+
+Examples:
+  task-tracker log 3 1h30m
+  task-tracker log 3 45m`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d, err := task.ParseDuration(args[1])
+		if err != nil {
+			return err
+		}
+
+		storage, err := newStorage()
+		if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		}
+		defer storage.Close()
+
+		id, err := storage.ResolveID(args[0])
+		if err != nil {
+			return err
+		}
+
+		logged, err := storage.LogWork(id, d)
+		if err != nil {
+			return fmt.Errorf("error logging work: %w", err)
+		}
+
+		notice(cmd, "Logged %s:\n", task.FormatDuration(d))
+		return printTask(cmd, *logged, storage)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(startCmd, stopCmd, logCmd)
+}
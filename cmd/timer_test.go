@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/render"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimerCommands(t *testing.T) {
+	storage, err := newStorage()
+	assert.NoError(t, err)
+	added, err := storage.AddTask("Timed", "")
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		storage.DeleteTask(context.Background(), added.ID)
+		rootCmd.SetArgs(nil)
+		outputFormat = string(render.FormatTable)
+	})
+
+	rootCmd.SetArgs([]string{"start", added.ID, "-o", "json"})
+	assert.NoError(t, rootCmd.Execute())
+
+	rootCmd.SetArgs([]string{"start", added.ID, "-o", "json"})
+	err = rootCmd.Execute()
+	assert.ErrorIs(t, err, task.ErrTimerRunning)
+	assert.Equal(t, exitConflict, exitCode(err))
+
+	rootCmd.SetArgs([]string{"stop", "-o", "json"})
+	assert.NoError(t, rootCmd.Execute())
+
+	rootCmd.SetArgs([]string{"log", added.ID, "1h30m", "-o", "json"})
+	assert.NoError(t, rootCmd.Execute())
+
+	rootCmd.SetArgs([]string{"log", added.ID, "later"})
+	assert.ErrorIs(t, rootCmd.Execute(), task.ErrValidation)
+
+	storage, err = newStorage()
+	assert.NoError(t, err)
+	defer storage.Close()
+	got, err := storage.GetTask(added.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, task.StatusInProgress, got.Status)
+	assert.Len(t, got.Work, 2)
+	assert.GreaterOrEqual(t, got.TimeSpent(time.Now()), 90*time.Minute)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright © 2025 Eddy Nio
+*/
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// undoCmd represents the undo command
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Revert the last change to your tasks",
+	Long: `The 'undo' command reverts the most recent change made to your tasks.
+
+Every add, update, delete, clear, repair and backup restore is recorded in a
+journal stored next to the tasks file. 'undo' reverts these operations one at a
+time, most recent first, and 'redo' re-applies what was undone. Making a new
+change discards the operations that could still be redone.
+
+If a task was modified after the operation being undone, the undo is refused
+unless --force is given.
+
+Examples:
+  task-tracker undo            # Revert the last change
+  task-tracker undo --list     # Show recent operations
+  task-tracker redo            # Re-apply the last undone change`,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+	var list, force bool
+	var limit int
+	undoCmd.Flags().BoolVarP(&list, "list", "l", false, "Show recent operations instead of undoing")
+	undoCmd.Flags().IntVarP(&limit, "limit", "n", 10, "Number of operations shown by --list (0 for all)")
+	undoCmd.Flags().BoolVarP(&force, "force", "f", false, "Undo even if the tasks changed since the operation")
+	undoCmd.Flags().SortFlags = false
+
+	undoCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		storage, err := newStorage()
+		if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		}
+		defer storage.Close()
+
+		out := cmd.OutOrStdout()
+		if list {
+			entries, err := storage.History(limit)
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				fmt.Fprintln(out, "No operations recorded")
+				return nil
+			}
+			for _, e := range entries {
+				state := "      "
+				if e.Undone {
+					state = "undone"
+				}
+				fmt.Fprintf(out, "#%-4d %s  %s  %-7s %s\n", e.Seq, e.At.Local().Format(time.RFC3339), state, e.Kind, e.Summary())
+			}
+			return nil
+		}
+
+		op, err := storage.Undo(force)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Undid #%d %s: %s\n", op.Seq, op.Kind, op.Summary())
+		return nil
+	}
+}
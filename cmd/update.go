@@ -5,8 +5,11 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/Eddy-Nio/task-tracker-cli/internal/render"
 	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
 	"github.com/spf13/cobra"
 )
@@ -17,57 +20,163 @@ var updateCmd = &cobra.Command{
 	Short: "Update an existing task",
 	Long: `The 'update' command allows you to modify an existing task in your task list.
 
-You can update various attributes of a task including its title, description, and status.
+You can update various attributes of a task including its title, description, status, priority and due date.
+Pass --due none to remove the due date. --remind sets how long before the due
+date 'watch' announces the task (0 clears it). Tags are added with --tag and removed
+with --untag, both repeatable. Use --parent to move the task under another task,
+or --parent none to make it a top-level task again.
+Starting a task (--status ip) that depends on unfinished tasks prints a warning,
+but the task is still updated.
+
+Use --estimate to set how much work the task is expected to take; 'list' and
+'show' compare it with the time spent.
+
+Use --repeat to make the task recurring (see 'add --help' for the rules), or
+--repeat none to stop it. Marking a recurring task as done adds its next
+occurrence, with the due date moved to the next date of the rule.
+
+The statuses, their aliases and the allowed status changes come from the workflow
+in the config file. Changes the workflow forbids, such as reopening a done task
+when the workflow makes DONE final, need --force.
 You'll need to provide the task ID and specify which fields you want to update.
 The changes will be saved to the local JSON file and the task's 'updated_at' timestamp
 will be automatically updated.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("update called")
-	},
 }
 
 func init() {
 	rootCmd.AddCommand(updateCmd)
-	var taskID, title, description, status string
+	var taskID, title, description, status, priority, due, remind, parent, estimate, repeat string
+	var addTags, removeTags []string
+	var force bool
 
-	updateCmd.Flags().StringVarP(&taskID, "id", "i", "", "Task ID to update")
+	updateCmd.Flags().StringVarP(&taskID, "id", "i", "", "Task ID or unique ID prefix to update")
 	updateCmd.Flags().StringVarP(&title, "title", "t", "", "New task title")
 	updateCmd.Flags().StringVarP(&description, "desc", "d", "", "New task description")
-	updateCmd.Flags().StringVarP(&status, "status", "s", "", "New task status (todo/t, in_progress/ip/p, done/d)")
+	updateCmd.Flags().StringVarP(&status, "status", "s", "", "New task status (todo/t, in_progress/ip/p, done/d or a status of the configured workflow)")
+	updateCmd.Flags().StringVarP(&priority, "priority", "p", "", "New task priority (low/l, medium/med/m, high/h, urgent/u)")
+	updateCmd.Flags().StringVar(&due, "due", "", "New due date (date format, YYYY-MM-DD, \"tomorrow\", \"in 3 days\" or \"none\")")
+	updateCmd.Flags().StringVar(&remind, "remind", "", "Remind this long before the due date, e.g. 1h (0 clears it)")
+	updateCmd.Flags().StringArrayVar(&addTags, "tag", nil, "Tag to add (repeatable)")
+	updateCmd.Flags().StringArrayVar(&removeTags, "untag", nil, "Tag to remove (repeatable)")
+	updateCmd.Flags().StringVar(&parent, "parent", "", "Make the task a subtask of this task (\"none\" for a top-level task)")
+	updateCmd.Flags().StringVar(&estimate, "estimate", "", "Expected work, e.g. 4h or 1h30m (0 clears it)")
+	updateCmd.Flags().StringVar(&repeat, "repeat", "", "Recurrence rule, e.g. weekly:mon,thu (\"none\" stops the recurrence)")
+	updateCmd.Flags().BoolVar(&force, "force", false, "Allow status changes the workflow forbids, e.g. reopening a done task")
 	updateCmd.MarkFlagRequired("id")
 	updateCmd.Flags().SortFlags = false
 
 	updateCmd.RunE = func(cmd *cobra.Command, args []string) error {
-		storage, err := task.NewTaskStorage("tasks.json")
+		storage, err := newStorage()
 		if err != nil {
-			return fmt.Errorf("failed to initialize storage: %v", err)
+			return fmt.Errorf("failed to initialize storage: %w", err)
 		}
+		defer storage.Close()
 
-		updates := make(map[string]interface{})
-		if title != "" {
-			updates["title"] = title
+		var patch task.TaskPatch
+		if cmd.Flags().Changed("title") {
+			patch.Title = &title
+		}
+		if cmd.Flags().Changed("desc") {
+			patch.Description = &description
+		}
+		if cmd.Flags().Changed("status") {
+			s := task.Status(status)
+			patch.Status = &s
+		}
+		if cmd.Flags().Changed("priority") {
+			p := task.Priority(priority)
+			patch.Priority = &p
+		}
+		if cmd.Flags().Changed("due") {
+			var dueAt time.Time
+			if due != "none" {
+				if dueAt, err = parseDate(due); err != nil {
+					return err
+				}
+			}
+			patch.DueAt = &dueAt
+		}
+		if cmd.Flags().Changed("remind") {
+			d, err := task.ParseDuration(remind)
+			if err != nil {
+				return err
+			}
+			patch.Reminder = &d
 		}
-		if description != "" {
-			updates["description"] = description
+		patch.AddTags = addTags
+		patch.RemoveTags = removeTags
+		if cmd.Flags().Changed("estimate") {
+			d, err := task.ParseDuration(estimate)
+			if err != nil {
+				return err
+			}
+			patch.Estimate = &d
 		}
-		if status != "" {
-			if _, err := task.ValidateStatus(status); err != nil {
-				return fmt.Errorf("invalid status: %v", err)
+		if cmd.Flags().Changed("repeat") {
+			rule := &task.Recurrence{}
+			if repeat != "none" {
+				if rule, err = task.ParseRecurrence(repeat); err != nil {
+					return err
+				}
+			}
+			patch.Recurrence = rule
+		}
+		patch.Force = force
+
+		if cmd.Flags().Changed("parent") {
+			var parentID string
+			if parent != "none" {
+				if parentID, err = storage.ResolveID(parent); err != nil {
+					return err
+				}
 			}
-			updates["status"] = status
+			patch.ParentID = &parentID
 		}
 
-		if len(updates) == 0 {
-			return fmt.Errorf("at least one field must be provided for update")
+		if patch.IsEmpty() {
+			return fmt.Errorf("at least one field must be provided for update: %w", task.ErrNoUpdatesProvided)
 		}
 
-		updatedTask, err := storage.UpdateTask(context.Background(), taskID, updates)
+		id, err := storage.ResolveID(taskID)
 		if err != nil {
-			return fmt.Errorf("error updating task: %v", err)
+			return err
 		}
 
-		fmt.Println("Task updated successfully:")
-		storage.PrintTask(*updatedTask)
+		previous, err := storage.GetTask(id)
+		if err != nil {
+			return err
+		}
+
+		if patch.Status != nil {
+			if s, err := storage.Workflow().ValidateStatus(status); err == nil && s == storage.Workflow().Started() {
+				if blockers := storage.BlockedBy()[id]; len(blockers) > 0 {
+					warnBlocked(cmd, id, blockers)
+				}
+			}
+		}
+
+		updatedTask, err := storage.UpdateTask(context.Background(), id, patch)
+		if errors.Is(err, task.ErrTransitionNotAllowed) {
+			return fmt.Errorf("error updating task: %w (use --force to override)", err)
+		}
+		if err != nil {
+			return fmt.Errorf("error updating task: %w", err)
+		}
+
+		notice(cmd, "Task updated successfully:\n")
+		if err := printTask(cmd, *updatedTask, storage); err != nil {
+			return err
+		}
+		// Machine readable formats get the updated task only; the next
+		// occurrence is linked by its next_id
+		if updatedTask.NextID != previous.NextID && render.IsHuman(outputFormat) {
+			next, err := storage.GetTask(updatedTask.NextID)
+			if err != nil {
+				return err
+			}
+			notice(cmd, "Next occurrence:\n")
+			return printTask(cmd, next, storage)
+		}
 		return nil
 	}
 }
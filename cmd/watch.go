@@ -0,0 +1,120 @@
+/*
+Copyright © 2025 Eddy Nio
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/notify"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+	"github.com/spf13/cobra"
+)
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:     "watch",
+	Aliases: []string{"daemon"},
+	Short:   "Notify about due tasks until interrupted",
+	Long: `The 'watch' command runs in the foreground and announces unfinished tasks
+when their due date arrives, and earlier when they have a reminder (add/update
+--remind, or notify.reminder in the config for every task). The tasks are read
+again at every check, so tasks added or updated meanwhile are picked up.
+
+Notifications are printed to stdout, shown on the desktop (notify-send, or
+osascript on macOS; printed to stdout on Windows) or passed to a shell hook, as set by notify.method and
+notify.command in the config or the flags below. The hook gets the task in the
+TASK_ID, TASK_TITLE, TASK_DUE, TASK_EVENT (due or reminder) and TASK_MESSAGE
+environment variables.
+
+Sent notifications are recorded next to the tasks file (tasks.json.notified by
+default, notify.stateFile in the config), so each one is sent once, even across
+restarts. Changing the due date or the reminder of a task arms it again.
+
+Examples:
+  task-tracker watch
+  task-tracker watch --method desktop --interval 1m
+  task-tracker watch --method command --command 'echo "$TASK_MESSAGE" >> ~/due.log'
+  task-tracker watch --once   # Check once and exit, e.g. from cron`,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	var method, command string
+	var once bool
+	watchCmd.Flags().StringVar(&method, "method", "", "Notification method: stdout, desktop or command (default from the config)")
+	watchCmd.Flags().StringVar(&command, "command", "", "Shell hook run by the command method (default from the config)")
+	watchCmd.Flags().Duration("interval", 0, "Time between checks (default from the config, 30s)")
+	watchCmd.Flags().Duration("reminder", 0, "Remind this long before the due date of tasks without a reminder")
+	watchCmd.Flags().BoolVar(&once, "once", false, "Check once and exit")
+	watchCmd.Flags().SortFlags = false
+
+	watchCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		// Fail early on a bad config or tasks file; the watcher opens the
+		// storage again on every change
+		storage, err := newStorage()
+		if err != nil {
+			return fmt.Errorf("error initializing storage: %w", err)
+		}
+		workflow := storage.Workflow()
+		storage.Close()
+
+		settings := cfg.Notify
+		if cmd.Flags().Changed("method") {
+			settings.Method = method
+		}
+		if cmd.Flags().Changed("command") {
+			settings.Command = command
+		}
+		if cmd.Flags().Changed("interval") {
+			settings.Interval, _ = cmd.Flags().GetDuration("interval")
+		}
+		if cmd.Flags().Changed("reminder") {
+			settings.Reminder, _ = cmd.Flags().GetDuration("reminder")
+		}
+		if settings.Interval <= 0 {
+			return &task.ValidationError{Field: "interval", Message: "the check interval must be longer than zero"}
+		}
+		if settings.StateFile == "" {
+			settings.StateFile = cfg.Storage.FilePath + ".notified"
+		}
+
+		notifier, err := notify.New(settings, cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+		state, err := notify.LoadState(settings.StateFile)
+		if err != nil {
+			return err
+		}
+
+		w := &notify.Watcher{
+			Path: cfg.Storage.FilePath,
+			Load: func() ([]task.Task, error) {
+				storage, err := newStorage()
+				if err != nil {
+					return nil, err
+				}
+				defer storage.Close()
+				return storage.ListTasks(), nil
+			},
+			Workflow: workflow,
+			Notifier: notifier,
+			State:    state,
+			Interval: settings.Interval,
+			Reminder: settings.Reminder,
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if once {
+			return w.Check(ctx)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Watching %s every %s, press Ctrl+C to stop\n", cfg.Storage.FilePath, settings.Interval)
+		return w.Run(ctx)
+	}
+}
@@ -1,50 +1,147 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
-type Config struct {
-	Storage struct {
-		FilePath  string `yaml:"filePath"`
-		BackupDir string `yaml:"backupDir"`
-	} `yaml:"storage"`
+const (
+	// AppName is the directory name used under the user's config home
+	AppName = "task-tracker"
+	// FileName is the name of the config file looked up in each location
+	FileName = "config.yaml"
+	// EnvPrefix is the prefix of environment variables overriding config values
+	EnvPrefix = "TASK_TRACKER"
+
+	// Storage backends
+	BackendJSON   = "json"
+	BackendSQLite = "sqlite"
+
+	// Task ID strategies
+	IDSequential = "sequential" // 1, 2, 3... per tasks file
+	IDULID       = "ulid"       // Sortable 26 character IDs
+	IDUUID       = "uuid"       // Random UUIDs
+	IDShort      = "short"      // 8 hex characters, the format used before strategies existed
+
+	// Notification methods of the watch command
+	NotifyStdout  = "stdout"  // Print a line per notification
+	NotifyDesktop = "desktop" // notify-send, or osascript on macOS
+	NotifyCommand = "command" // Run the configured shell hook
+)
+
+// StorageConfig holds the settings of the task storage
+type StorageConfig struct {
+	Backend   string `yaml:"backend"` // json or sqlite
+	FilePath  string `yaml:"filePath"`
+	BackupDir string `yaml:"backupDir"`
+	// RecoverCorrupt starts with an empty task list after quarantining a
+	// corrupt tasks file instead of refusing to continue
+	RecoverCorrupt bool `yaml:"recoverCorrupt"`
+	// LockTimeout is how long to wait for another process to release the tasks file
+	LockTimeout time.Duration `yaml:"lockTimeout"`
+}
+
+// TaskConfig holds the settings applied to tasks
+type TaskConfig struct {
+	MaxTitleLength       int            `yaml:"maxTitleLength"`
+	MaxDescriptionLength int            `yaml:"maxDescriptionLength"`
+	DateFormat           string         `yaml:"dateFormat"`
+	AutoBackup           bool           `yaml:"autoBackup"`
+	BackupInterval       time.Duration  `yaml:"backupInterval"`
+	BackupKeepDaily      int            `yaml:"backupKeepDaily"`  // Days for which the newest backup is kept
+	BackupKeepWeekly     int            `yaml:"backupKeepWeekly"` // Weeks for which the newest backup is kept
+	Actor                string         `yaml:"actor"`            // Name recorded in task history, defaults to the OS user
+	IDStrategy           string         `yaml:"idStrategy"`       // How new task IDs are generated: sequential, ulid, uuid or short
+	Workflow             WorkflowConfig `yaml:"workflow"`         // Statuses tasks go through
+}
 
-	Task struct {
-		MaxTitleLength       int           `yaml:"maxTitleLength"`
-		MaxDescriptionLength int           `yaml:"maxDescriptionLength"`
-		DateFormat           string        `yaml:"dateFormat"`
-		AutoBackup           bool          `yaml:"autoBackup"`
-		BackupInterval       time.Duration `yaml:"backupInterval"`
-	} `yaml:"task"`
+// WorkflowConfig defines the statuses of tasks and the moves allowed between them
+type WorkflowConfig struct {
+	Statuses []StatusConfig `yaml:"statuses"`
+	// Initial is the status of new tasks, the first of Statuses when empty
+	Initial string `yaml:"initial"`
+	// Started is the status of tasks being worked on, which starting a timer
+	// moves a task to and reports measure cycle time from. When empty it is
+	// the first of Statuses that is neither initial nor done.
+	Started string `yaml:"started"`
+	// Transitions lists, for a status, the statuses a task may move to from it.
+	// Statuses missing from the map may move to any status; an empty list
+	// makes a status final. Forbidden moves need --force.
+	Transitions map[string][]string `yaml:"transitions"`
+}
+
+// StatusConfig defines a task status
+type StatusConfig struct {
+	Name    string   `yaml:"name"`    // Upper-case name, e.g. REVIEW
+	Aliases []string `yaml:"aliases"` // Short names accepted on the command line, besides the lower-cased name
+	// Done marks statuses ending the work on a task: done tasks are never
+	// overdue, don't block the tasks depending on them and count as done in
+	// the progress of their parent
+	Done bool `yaml:"done"`
+}
+
+// NotifyConfig holds the settings of the watch command, which notifies about
+// due tasks
+type NotifyConfig struct {
+	Method string `yaml:"method"` // stdout, desktop or command
+	// Command is the shell hook run for every notification by the command
+	// method. The task is passed in the TASK_ID, TASK_TITLE, TASK_DUE,
+	// TASK_EVENT (due or reminder) and TASK_MESSAGE environment variables.
+	Command  string        `yaml:"command"`
+	Interval time.Duration `yaml:"interval"` // How often due dates are checked
+	// Reminder is how long before their due date tasks without a reminder of
+	// their own are announced, 0 for none
+	Reminder time.Duration `yaml:"reminder"`
+	// StateFile records the notifications sent, so none is sent twice across
+	// restarts. It defaults to the tasks file followed by ".notified".
+	StateFile string `yaml:"stateFile"`
+}
+
+type Config struct {
+	Storage StorageConfig `yaml:"storage"`
+	Task    TaskConfig    `yaml:"task"`
+	Notify  NotifyConfig  `yaml:"notify"`
 }
 
 var DefaultConfig = Config{
-	Storage: struct {
-		FilePath  string `yaml:"filePath"`
-		BackupDir string `yaml:"backupDir"`
-	}{
-		FilePath:  "tasks.json",
-		BackupDir: "backups",
+	Storage: StorageConfig{
+		Backend:     BackendJSON,
+		FilePath:    "tasks.json",
+		BackupDir:   "backups",
+		LockTimeout: 5 * time.Second,
 	},
-	Task: struct {
-		MaxTitleLength       int           `yaml:"maxTitleLength"`
-		MaxDescriptionLength int           `yaml:"maxDescriptionLength"`
-		DateFormat           string        `yaml:"dateFormat"`
-		AutoBackup           bool          `yaml:"autoBackup"`
-		BackupInterval       time.Duration `yaml:"backupInterval"`
-	}{
+	Task: TaskConfig{
 		MaxTitleLength:       50,
 		MaxDescriptionLength: 200,
 		DateFormat:           time.RFC3339,
 		AutoBackup:           true,
 		BackupInterval:       24 * time.Hour,
+		BackupKeepDaily:      7,
+		BackupKeepWeekly:     4,
+		IDStrategy:           IDSequential,
+		Workflow: WorkflowConfig{
+			Statuses: []StatusConfig{
+				{Name: "TODO", Aliases: []string{"t"}},
+				{Name: "IN_PROGRESS", Aliases: []string{"ip", "p"}},
+				{Name: "DONE", Aliases: []string{"d"}, Done: true},
+			},
+		},
+	},
+	Notify: NotifyConfig{
+		Method:   NotifyStdout,
+		Interval: 30 * time.Second,
 	},
 }
 
+// LoadConfig reads the config file at path on top of the defaults.
+// A missing file is not an error: the defaults are returned instead.
 func LoadConfig(path string) (*Config, error) {
 	cfg := DefaultConfig
 
@@ -53,14 +150,182 @@ func LoadConfig(path string) (*Config, error) {
 		return &cfg, nil
 	}
 
-	data, err := os.ReadFile(path)
-	if err != nil {
+	if err := mergeFile(&cfg, path); err != nil {
 		return nil, err
 	}
 
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	return &cfg, nil
+}
+
+// Load resolves the configuration used by the CLI.
+//
+// When path is set, only that file is read and it must exist. Otherwise the
+// user config ($XDG_CONFIG_HOME/task-tracker/config.yaml) is applied first and
+// the config.yaml of the working directory second, so a project can override
+// the user's settings. Environment variables are applied last.
+func Load(path string) (*Config, error) {
+	cfg := DefaultConfig
+
+	if path != "" {
+		if err := mergeFile(&cfg, path); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, candidate := range SearchPaths() {
+			if _, err := os.Stat(candidate); err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return nil, fmt.Errorf("checking config file %s: %w", candidate, err)
+			}
+			if err := mergeFile(&cfg, candidate); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := applyEnv(&cfg, os.LookupEnv); err != nil {
 		return nil, err
 	}
 
 	return &cfg, nil
 }
+
+// SearchPaths returns the default config file locations, lowest precedence first
+func SearchPaths() []string {
+	var paths []string
+
+	if dir := userConfigDir(); dir != "" {
+		paths = append(paths, filepath.Join(dir, AppName, FileName))
+	}
+
+	return append(paths, FileName)
+}
+
+// userConfigDir returns $XDG_CONFIG_HOME, falling back to ~/.config
+func userConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config")
+}
+
+// mergeFile decodes the YAML file at path into cfg. Relative storage and
+// state file paths set by the file are resolved against the directory
+// containing it.
+func mergeFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+
+	// Relative paths set by the file are relative to it, even when they equal
+	// the value of a previous layer
+	var paths struct {
+		Storage struct {
+			FilePath  *string `yaml:"filePath"`
+			BackupDir *string `yaml:"backupDir"`
+		} `yaml:"storage"`
+		Notify struct {
+			StateFile *string `yaml:"stateFile"`
+		} `yaml:"notify"`
+	}
+	if err := yaml.Unmarshal(data, &paths); err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	if paths.Storage.FilePath != nil {
+		cfg.Storage.FilePath = resolvePath(dir, cfg.Storage.FilePath)
+	}
+	if paths.Storage.BackupDir != nil {
+		cfg.Storage.BackupDir = resolvePath(dir, cfg.Storage.BackupDir)
+	}
+	if paths.Notify.StateFile != nil {
+		cfg.Notify.StateFile = resolvePath(dir, cfg.Notify.StateFile)
+	}
+
+	return nil
+}
+
+func resolvePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// applyEnv overrides cfg with TASK_TRACKER_<SECTION>_<KEY> variables, where
+// SECTION and KEY are the upper-cased YAML keys (e.g. TASK_TRACKER_STORAGE_FILEPATH).
+func applyEnv(cfg *Config, lookup func(string) (string, bool)) error {
+	root := reflect.ValueOf(cfg).Elem()
+
+	for i := 0; i < root.NumField(); i++ {
+		section := root.Field(i)
+		sectionKey := yamlKey(root.Type().Field(i))
+
+		for j := 0; j < section.NumField(); j++ {
+			field := section.Type().Field(j)
+			name := strings.ToUpper(EnvPrefix + "_" + sectionKey + "_" + yamlKey(field))
+
+			value, ok := lookup(name)
+			if !ok {
+				continue
+			}
+			if err := setValue(section.Field(j), value); err != nil {
+				return fmt.Errorf("invalid value for %s: %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func yamlKey(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func setValue(v reflect.Value, raw string) error {
+	switch v.Interface().(type) {
+	case time.Duration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
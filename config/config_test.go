@@ -0,0 +1,172 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	xdg := t.TempDir()
+	work := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(work); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	writeConfig(t, filepath.Join(xdg, AppName, FileName), "task:\n  maxTitleLength: 80\n  maxDescriptionLength: 300\n")
+	writeConfig(t, filepath.Join(work, FileName), "storage:\n  filePath: project.json\ntask:\n  maxTitleLength: 60\n")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.Task.MaxTitleLength != 60 {
+		t.Errorf("Expected project config to win, got maxTitleLength %d", cfg.Task.MaxTitleLength)
+	}
+	if cfg.Task.MaxDescriptionLength != 300 {
+		t.Errorf("Expected user config value 300, got %d", cfg.Task.MaxDescriptionLength)
+	}
+	if cfg.Storage.FilePath != "project.json" {
+		t.Errorf("Expected file path project.json, got %s", cfg.Storage.FilePath)
+	}
+	if cfg.Storage.BackupDir != DefaultConfig.Storage.BackupDir {
+		t.Errorf("Expected default backup dir, got %s", cfg.Storage.BackupDir)
+	}
+}
+
+func TestLoad_UserConfigPaths(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	// The same values as the defaults, still relative to the config file
+	writeConfig(t, filepath.Join(xdg, AppName, FileName), "storage:\n  filePath: tasks.json\n  backupDir: backups\n")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := filepath.Join(xdg, AppName, "tasks.json"); cfg.Storage.FilePath != want {
+		t.Errorf("Expected file path %s, got %s", want, cfg.Storage.FilePath)
+	}
+	if want := filepath.Join(xdg, AppName, "backups"); cfg.Storage.BackupDir != want {
+		t.Errorf("Expected backup dir %s, got %s", want, cfg.Storage.BackupDir)
+	}
+}
+
+func TestLoad_ExplicitPath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "custom.yaml")
+	writeConfig(t, path, "storage:\n  filePath: shared/tasks.json\n  backupDir: /var/backups/tasks\nnotify:\n  stateFile: state/notified.json\n")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if want := filepath.Join(dir, "shared", "tasks.json"); cfg.Storage.FilePath != want {
+		t.Errorf("Expected file path %s, got %s", want, cfg.Storage.FilePath)
+	}
+	if cfg.Storage.BackupDir != "/var/backups/tasks" {
+		t.Errorf("Expected absolute backup dir to be kept, got %s", cfg.Storage.BackupDir)
+	}
+	if want := filepath.Join(dir, "state", "notified.json"); cfg.Notify.StateFile != want {
+		t.Errorf("Expected state file %s, got %s", want, cfg.Notify.StateFile)
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("Expected error for missing explicit config file")
+	}
+}
+
+func TestLoad_EnvOverrides(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("TASK_TRACKER_STORAGE_FILEPATH", "/tmp/team/tasks.json")
+	t.Setenv("TASK_TRACKER_TASK_MAXTITLELENGTH", "120")
+	t.Setenv("TASK_TRACKER_TASK_AUTOBACKUP", "false")
+	t.Setenv("TASK_TRACKER_TASK_BACKUPINTERVAL", "2h")
+	t.Setenv("TASK_TRACKER_NOTIFY_METHOD", "desktop")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.Storage.FilePath != "/tmp/team/tasks.json" {
+		t.Errorf("Expected env file path, got %s", cfg.Storage.FilePath)
+	}
+	if cfg.Task.MaxTitleLength != 120 {
+		t.Errorf("Expected maxTitleLength 120, got %d", cfg.Task.MaxTitleLength)
+	}
+	if cfg.Task.AutoBackup {
+		t.Error("Expected autoBackup to be disabled")
+	}
+	if cfg.Task.BackupInterval != 2*time.Hour {
+		t.Errorf("Expected backupInterval 2h, got %s", cfg.Task.BackupInterval)
+	}
+	if cfg.Notify.Method != NotifyDesktop || cfg.Notify.Interval != 30*time.Second {
+		t.Errorf("Expected the desktop method with the default interval, got %+v", cfg.Notify)
+	}
+
+	t.Setenv("TASK_TRACKER_TASK_MAXTITLELENGTH", "many")
+	if _, err := Load(""); err == nil {
+		t.Error("Expected error for invalid integer override")
+	}
+}
+
+func TestLoadConfig_Workflow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	writeConfig(t, path, `task:
+  workflow:
+    statuses:
+      - name: OPEN
+        aliases: [o]
+      - name: CLOSED
+        done: true
+    transitions:
+      CLOSED: []
+`)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	statuses := cfg.Task.Workflow.Statuses
+	if len(statuses) != 2 || statuses[0].Name != "OPEN" || statuses[0].Done || !statuses[1].Done {
+		t.Errorf("Expected the configured statuses to replace the default ones, got %+v", statuses)
+	}
+	if targets, ok := cfg.Task.Workflow.Transitions["CLOSED"]; !ok || len(targets) != 0 {
+		t.Errorf("Expected CLOSED to be final, got %v", cfg.Task.Workflow.Transitions)
+	}
+	if defaults := DefaultConfig.Task.Workflow.Statuses; len(defaults) != 3 || defaults[0].Name != "TODO" {
+		t.Errorf("Loading a config must not change the default workflow, got %+v", DefaultConfig.Task.Workflow.Statuses)
+	}
+}
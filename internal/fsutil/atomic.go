@@ -0,0 +1,72 @@
+// Package fsutil provides file system helpers shared by the storage code.
+package fsutil
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/logger"
+	"go.uber.org/zap"
+)
+
+// file is the subset of *os.File used by WriteFile. It lets tests replace the
+// temporary file with one that fails part way through a write.
+type file interface {
+	io.Writer
+	Name() string
+	Sync() error
+	Close() error
+	Chmod(mode os.FileMode) error
+}
+
+var (
+	createTemp = func(dir, pattern string) (file, error) { return os.CreateTemp(dir, pattern) }
+	rename     = os.Rename
+	dirSync    = syncDir
+)
+
+// WriteFile atomically replaces the file at path with data.
+//
+// The data is written to a temporary file in the same directory, flushed to
+// disk and renamed over path, after which the directory itself is synced so
+// the rename survives a crash. Readers see either the previous content or the
+// new one, never a partially written file. Once the rename succeeded the new
+// content is in place, so failing to sync the directory is only logged.
+func WriteFile(path string, data []byte, perm os.FileMode) (err error) {
+	dir := filepath.Dir(path)
+
+	tmp, err := createTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return fmt.Errorf("writing temporary file: %w", err)
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return fmt.Errorf("setting file permissions: %w", err)
+	}
+	if err = tmp.Sync(); err != nil {
+		return fmt.Errorf("syncing temporary file: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("closing temporary file: %w", err)
+	}
+	if err = rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replacing %s: %w", path, err)
+	}
+
+	if err := dirSync(dir); err != nil {
+		logger.Error("syncing directory failed", zap.String("dir", dir), zap.Error(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package fsutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var errDiskFull = errors.New("no space left on device")
+
+// partialFile wraps a real temporary file and fails after limit bytes,
+// simulating a full disk or a crash in the middle of a write.
+type partialFile struct {
+	*os.File
+	limit int
+}
+
+func (f *partialFile) Write(p []byte) (int, error) {
+	if len(p) <= f.limit {
+		return f.File.Write(p)
+	}
+	n, _ := f.File.Write(p[:f.limit])
+	return n, errDiskFull
+}
+
+func withPartialWrites(t *testing.T, limit int) {
+	original := createTemp
+	createTemp = func(dir, pattern string) (file, error) {
+		f, err := os.CreateTemp(dir, pattern)
+		if err != nil {
+			return nil, err
+		}
+		return &partialFile{File: f, limit: limit}, nil
+	}
+	t.Cleanup(func() { createTemp = original })
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+
+	if err := WriteFile(path, []byte(`[{"id":"1"}]`), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := WriteFile(path, []byte(`[]`), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(data) != `[]` {
+		t.Errorf("Expected replaced content, got %s", data)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("Expected mode 0644, got %v", info.Mode().Perm())
+	}
+}
+
+func TestWriteFile_PartialWritesKeepPreviousVersion(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tasks.json")
+	previous := []byte(`[{"id":"1","title":"keep me"}]`)
+	next := []byte(`[{"id":"1","title":"keep me"},{"id":"2","title":"new task"}]`)
+
+	if err := os.WriteFile(path, previous, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fail the write at every possible offset
+	for limit := 0; limit < len(next); limit++ {
+		withPartialWrites(t, limit)
+
+		err := WriteFile(path, next, 0644)
+		if !errors.Is(err, errDiskFull) {
+			t.Fatalf("limit %d: expected disk full error, got %v", limit, err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("limit %d: failed to read file: %v", limit, err)
+		}
+		if string(data) != string(previous) {
+			t.Fatalf("limit %d: previous version was damaged: %s", limit, data)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected temporary files to be cleaned up, found %d entries", len(entries))
+	}
+}
+
+func TestWriteFile_RenameFailureKeepsPreviousVersion(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tasks.json")
+	if err := os.WriteFile(path, []byte(`[]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	original := rename
+	rename = func(string, string) error { return errors.New("crash before rename") }
+	defer func() { rename = original }()
+
+	if err := WriteFile(path, []byte(`[{"id":"1"}]`), 0644); err == nil {
+		t.Fatal("Expected error when rename fails")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `[]` {
+		t.Errorf("Expected previous content, got %s", data)
+	}
+}
+
+func TestWriteFile_DirSyncFailureKeepsNewVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+
+	original := dirSync
+	dirSync = func(string) error { return errors.New("sync not supported") }
+	defer func() { dirSync = original }()
+
+	// The file was replaced, so the caller must not treat the write as failed
+	if err := WriteFile(path, []byte(`[{"id":"1"}]`), 0644); err != nil {
+		t.Fatalf("Expected no error once the file was replaced, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `[{"id":"1"}]` {
+		t.Errorf("Expected new content, got %s", data)
+	}
+}
@@ -0,0 +1,56 @@
+package fsutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ErrLockTimeout is returned when a lock is still held by someone else after the wait timeout
+var ErrLockTimeout = errors.New("timed out waiting for lock")
+
+// lockRetryInterval is how often a held lock is retried while waiting
+const lockRetryInterval = 20 * time.Millisecond
+
+// Lock is an exclusive advisory lock held on a lock file
+type Lock struct {
+	f *os.File
+}
+
+// AcquireLock takes an exclusive advisory lock on the file at path, creating
+// it if needed. It waits up to timeout for other holders to release it and
+// returns an error wrapping ErrLockTimeout if they don't.
+func AcquireLock(path string, timeout time.Duration) (*Lock, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLock(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("locking %s: %w", path, err)
+		}
+		if locked {
+			return &Lock{f: f}, nil
+		}
+		if !time.Now().Before(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("%w %s after %s", ErrLockTimeout, path, timeout)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// Release unlocks and closes the lock file. The file itself is left in place
+// so that every process keeps locking the same inode.
+func (l *Lock) Release() error {
+	if err := unlock(l.f); err != nil {
+		l.f.Close()
+		return err
+	}
+	return l.f.Close()
+}
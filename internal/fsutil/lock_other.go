@@ -0,0 +1,15 @@
+//go:build !unix
+
+package fsutil
+
+import "os"
+
+// tryLock always succeeds where flock is unavailable; concurrent invocations
+// are then only protected by the atomic writes of WriteFile.
+func tryLock(f *os.File) (bool, error) {
+	return true, nil
+}
+
+func unlock(f *os.File) error {
+	return nil
+}
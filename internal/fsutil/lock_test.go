@@ -0,0 +1,44 @@
+//go:build unix
+
+package fsutil
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAcquireLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json.lock")
+
+	held, err := AcquireLock(path, time.Second)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	start := time.Now()
+	_, err = AcquireLock(path, 100*time.Millisecond)
+	if !errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("Expected ErrLockTimeout while the lock is held, got %v", err)
+	}
+	if waited := time.Since(start); waited < 100*time.Millisecond {
+		t.Errorf("Expected to wait for the timeout, waited %s", waited)
+	}
+
+	released := make(chan struct{})
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		held.Release()
+		close(released)
+	}()
+
+	second, err := AcquireLock(path, time.Second)
+	if err != nil {
+		t.Fatalf("Expected lock after release, got %v", err)
+	}
+	<-released
+	if err := second.Release(); err != nil {
+		t.Errorf("Unexpected error releasing lock: %v", err)
+	}
+}
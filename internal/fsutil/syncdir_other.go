@@ -0,0 +1,8 @@
+//go:build !unix
+
+package fsutil
+
+// syncDir is a no-op where directories cannot be opened for syncing (e.g. Windows)
+func syncDir(dir string) error {
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build darwin
+
+package notify
+
+import (
+	"context"
+	"os/exec"
+)
+
+func desktopCommand(ctx context.Context, title, message string) *exec.Cmd {
+	script := "display notification " + appleScriptString(message) + " with title " + appleScriptString(title)
+	return exec.CommandContext(ctx, "osascript", "-e", script)
+}
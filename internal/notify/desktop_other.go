@@ -0,0 +1,12 @@
+//go:build !darwin && !windows
+
+package notify
+
+import (
+	"context"
+	"os/exec"
+)
+
+func desktopCommand(ctx context.Context, title, message string) *exec.Cmd {
+	return exec.CommandContext(ctx, "notify-send", title, message)
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package notify
+
+import (
+	"context"
+	"os/exec"
+)
+
+// desktopCommand returns nil: Windows has no notification command that works
+// without extra modules, so Desktop writes to the terminal instead
+func desktopCommand(ctx context.Context, title, message string) *exec.Cmd {
+	return nil
+}
@@ -0,0 +1,156 @@
+// Package notify announces tasks whose due date or reminder has arrived, for
+// the watch command
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/config"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+)
+
+// Kind is what a notification announces
+type Kind string
+
+const (
+	KindReminder Kind = "reminder" // The reminder offset before the due date has arrived
+	KindDue      Kind = "due"      // The due date has arrived
+)
+
+// Event is a notification about a task
+type Event struct {
+	Kind Kind
+	Task task.Task
+}
+
+// Key identifies the event in the state file. It includes the due date and
+// the reminder offset, so moving either one announces the task again.
+func (e Event) Key() string {
+	due := e.Task.DueAt.UTC().Format(time.RFC3339)
+	if e.Kind == KindReminder {
+		return fmt.Sprintf("%s/%s/%s/%s", e.Task.ID, e.Kind, due, e.Task.Reminder)
+	}
+	return fmt.Sprintf("%s/%s/%s", e.Task.ID, e.Kind, due)
+}
+
+// Message describes the event in a sentence
+func (e Event) Message(now time.Time) string {
+	if e.Kind == KindReminder {
+		return fmt.Sprintf("Task %s %q is due in %s", e.Task.ID, e.Task.Title, task.FormatDuration(e.Task.DueAt.Sub(now)))
+	}
+	return fmt.Sprintf("Task %s %q is due", e.Task.ID, e.Task.Title)
+}
+
+// Pending returns the events that have arrived at now for the tasks with a
+// due date not done in workflow w, due events first. reminder is the offset used for
+// tasks without a reminder of their own. A task past its due date gets a due
+// event only: its reminder is outdated.
+func Pending(tasks []task.Task, w *task.Workflow, now time.Time, reminder time.Duration) []Event {
+	var events []Event
+	for _, t := range tasks {
+		if t.DueAt == nil || w.IsDone(t.Status) {
+			continue
+		}
+		if t.Reminder == 0 {
+			t.Reminder = reminder
+		}
+		switch {
+		case !now.Before(*t.DueAt):
+			events = append(events, Event{Kind: KindDue, Task: t})
+		case t.Reminder > 0 && !now.Before(t.DueAt.Add(-t.Reminder)):
+			events = append(events, Event{Kind: KindReminder, Task: t})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Kind == KindDue && events[j].Kind != KindDue
+	})
+	return events
+}
+
+// Notifier delivers notifications
+type Notifier interface {
+	Notify(ctx context.Context, e Event, now time.Time) error
+}
+
+// New returns the notifier selected by cfg.Method. The stdout method, the
+// shell hook of the command method and desktop notifications on systems
+// without a notification command write to out.
+func New(cfg config.NotifyConfig, out io.Writer) (Notifier, error) {
+	switch cfg.Method {
+	case "", config.NotifyStdout:
+		return Stdout{Out: out}, nil
+	case config.NotifyDesktop:
+		return Desktop{Out: out}, nil
+	case config.NotifyCommand:
+		if cfg.Command == "" {
+			return nil, &task.ValidationError{Field: "notify", Message: "the command notification method requires notify.command in the config"}
+		}
+		return Command{Command: cfg.Command, Out: out}, nil
+	}
+	return nil, &task.ValidationError{Field: "notify", Message: fmt.Sprintf("invalid notification method %q. Use one of: %s, %s, %s",
+		cfg.Method, config.NotifyStdout, config.NotifyDesktop, config.NotifyCommand)}
+}
+
+// Stdout writes a line per notification
+type Stdout struct {
+	Out io.Writer
+}
+
+func (n Stdout) Notify(_ context.Context, e Event, now time.Time) error {
+	_, err := fmt.Fprintf(n.Out, "%s  %s\n", now.Format(time.RFC3339), e.Message(now))
+	return err
+}
+
+// Desktop shows a desktop notification with notify-send, or osascript on
+// macOS. On Windows it writes to Out like Stdout.
+type Desktop struct {
+	Out io.Writer
+}
+
+func (n Desktop) Notify(ctx context.Context, e Event, now time.Time) error {
+	cmd := desktopCommand(ctx, "task-tracker", e.Message(now))
+	if cmd == nil {
+		return Stdout{Out: n.Out}.Notify(ctx, e, now)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("desktop notification failed: %w: %s", err, out)
+	}
+	return nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal, which only
+// knows the \\ and \" escapes
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// Command runs a shell hook for every notification, passing the task in the
+// TASK_ID, TASK_TITLE, TASK_DUE, TASK_EVENT and TASK_MESSAGE environment
+// variables. Its output goes to Out.
+type Command struct {
+	Command string
+	Out     io.Writer
+}
+
+func (n Command) Notify(ctx context.Context, e Event, now time.Time) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", n.Command)
+	cmd.Env = append(os.Environ(),
+		"TASK_ID="+e.Task.ID,
+		"TASK_TITLE="+e.Task.Title,
+		"TASK_DUE="+e.Task.DueAt.Format(time.RFC3339),
+		"TASK_EVENT="+string(e.Kind),
+		"TASK_MESSAGE="+e.Message(now),
+	)
+	cmd.Stdout, cmd.Stderr = n.Out, n.Out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("notification command failed: %w", err)
+	}
+	return nil
+}
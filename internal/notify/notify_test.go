@@ -0,0 +1,189 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/config"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+)
+
+// recorder is a Notifier remembering the events it was given
+type recorder struct {
+	events []Event
+	err    error
+}
+
+func (r *recorder) Notify(_ context.Context, e Event, _ time.Time) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.events = append(r.events, e)
+	return nil
+}
+
+func dueIn(id string, d, reminder time.Duration, now time.Time) task.Task {
+	due := now.Add(d)
+	return task.Task{ID: id, Title: "Task " + id, Status: task.StatusTodo, DueAt: &due, Reminder: reminder}
+}
+
+func TestPending(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	done := dueIn("4", -time.Hour, 0, now)
+	done.Status = task.StatusDone
+
+	tasks := []task.Task{
+		dueIn("1", 30*time.Minute, time.Hour, now), // Reminder arrived
+		dueIn("2", -time.Minute, time.Hour, now),   // Overdue: due only
+		dueIn("3", 2*time.Hour, time.Hour, now),    // Nothing yet
+		done,
+		{ID: "5", Status: task.StatusTodo}, // No due date
+		dueIn("6", 10*time.Minute, 0, now), // Default reminder
+	}
+
+	events := Pending(tasks, task.DefaultWorkflow, now, 15*time.Minute)
+	want := []struct {
+		id   string
+		kind Kind
+	}{{"2", KindDue}, {"1", KindReminder}, {"6", KindReminder}}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events, got %+v", len(want), events)
+	}
+	for i, w := range want {
+		if events[i].Task.ID != w.id || events[i].Kind != w.kind {
+			t.Errorf("Expected %s %s at %d, got %s %s", w.kind, w.id, i, events[i].Kind, events[i].Task.ID)
+		}
+	}
+}
+
+func TestState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json.notified")
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	e := Event{Kind: KindDue, Task: dueIn("1", 0, 0, now)}
+
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Mark(e, now)
+	state.Mark(Event{Kind: KindDue, Task: dueIn("2", 0, 0, now)}, now)
+	state.Prune([]task.Task{e.Task})
+	if err := state.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.Done(e) || len(loaded.Sent) != 1 {
+		t.Errorf("Expected only the event of task 1 to be recorded, got %v", loaded.Sent)
+	}
+
+	// Moving the due date arms the task again
+	moved := e
+	moved.Task = dueIn("1", time.Hour, 0, now)
+	if loaded.Done(moved) {
+		t.Error("Expected a new due date to be a new event")
+	}
+}
+
+func TestWatcher_Check(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	state, err := LoadState(path + ".notified")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	tasks := []task.Task{dueIn("1", -time.Minute, 0, now)}
+	notifier := &recorder{}
+	w := &Watcher{
+		Path:     path,
+		Load:     func() ([]task.Task, error) { return tasks, nil },
+		Notifier: notifier,
+		State:    state,
+		Interval: time.Second,
+		Now:      func() time.Time { return now },
+	}
+	ctx := context.Background()
+
+	if err := w.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.events) != 1 {
+		t.Errorf("Expected one notification, got %d", len(notifier.events))
+	}
+
+	// The store changes without the tasks file changing, as with sqlite
+	// writing to its write-ahead log: the new task is picked up
+	tasks = append(tasks, dueIn("2", -time.Minute, 0, now))
+	if err := w.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.events) != 2 || notifier.events[1].Task.ID != "2" {
+		t.Errorf("Expected the new task to be announced, got %+v", notifier.events)
+	}
+
+	// A restarted watcher doesn't repeat the notifications
+	restored, err := LoadState(path + ".notified")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again := &recorder{}
+	restarted := &Watcher{Path: path, Load: w.Load, Notifier: again, State: restored, Interval: time.Second, Now: w.Now}
+	if err := restarted.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(again.events) != 0 {
+		t.Errorf("Expected no notification after a restart, got %+v", again.events)
+	}
+
+	// Failed notifications are retried
+	tasks = append(tasks, dueIn("3", -time.Minute, 0, now))
+	again.err = errors.New("no display")
+	if err := restarted.Check(ctx); err == nil {
+		t.Fatal("Expected the notifier error")
+	}
+	again.err = nil
+	if err := restarted.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(again.events) != 1 || again.events[0].Task.ID != "3" {
+		t.Errorf("Expected the failed notification to be sent again, got %+v", again.events)
+	}
+}
+
+func TestAppleScriptString(t *testing.T) {
+	got := appleScriptString(`Task 1 "Ship" \ café`)
+	if want := `"Task 1 \"Ship\" \\ café"`; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(configWith("command", ""), os.Stdout); !errors.Is(err, task.ErrValidation) {
+		t.Errorf("Expected a validation error without a command, got %v", err)
+	}
+	if _, err := New(configWith("pager", ""), os.Stdout); !errors.Is(err, task.ErrValidation) {
+		t.Errorf("Expected a validation error for an unknown method, got %v", err)
+	}
+	if n, err := New(configWith("command", "true"), os.Stdout); err != nil {
+		t.Errorf("Expected a command notifier, got %v", err)
+	} else if err := n.Notify(context.Background(), Event{Kind: KindDue, Task: dueIn("1", 0, 0, time.Now())}, time.Now()); err != nil {
+		t.Errorf("Running the hook failed: %v", err)
+	}
+}
+
+func configWith(method, command string) config.NotifyConfig {
+	cfg := config.DefaultConfig.Notify
+	cfg.Method, cfg.Command = method, command
+	return cfg
+}
@@ -0,0 +1,75 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/fsutil"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+)
+
+// State records the notifications already sent, keyed by Event.Key, so that
+// restarting the watcher doesn't send them again
+type State struct {
+	path string
+	Sent map[string]time.Time `json:"sent"`
+}
+
+// LoadState reads the state file at path. A missing file is an empty state.
+func LoadState(path string) (*State, error) {
+	s := &State{path: path, Sent: make(map[string]time.Time)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading notification state: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("error parsing notification state %s: %w", path, err)
+	}
+	if s.Sent == nil {
+		s.Sent = make(map[string]time.Time)
+	}
+	return s, nil
+}
+
+// Done reports whether the event was already sent
+func (s *State) Done(e Event) bool {
+	_, ok := s.Sent[e.Key()]
+	return ok
+}
+
+// Mark records that the event was sent at the given time
+func (s *State) Mark(e Event, at time.Time) {
+	s.Sent[e.Key()] = at
+}
+
+// Prune forgets the notifications of tasks that no longer exist
+func (s *State) Prune(tasks []task.Task) {
+	ids := make(map[string]bool, len(tasks))
+	for _, t := range tasks {
+		ids[t.ID] = true
+	}
+	for key := range s.Sent {
+		if id, _, _ := strings.Cut(key, "/"); !ids[id] {
+			delete(s.Sent, key)
+		}
+	}
+}
+
+// Save writes the state file atomically
+func (s *State) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing notification state: %w", err)
+	}
+	if err := fsutil.WriteFile(s.path, data, 0o644); err != nil {
+		return fmt.Errorf("error saving notification state: %w", err)
+	}
+	return nil
+}
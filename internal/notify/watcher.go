@@ -0,0 +1,110 @@
+package notify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/logger"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+	"go.uber.org/zap"
+)
+
+// Watcher checks the tasks at a regular interval and sends the notifications
+// that have arrived, each one once
+type Watcher struct {
+	Path     string                      // Tasks file, named in log messages
+	Load     func() ([]task.Task, error) // Reads the tasks
+	Workflow *task.Workflow              // Done statuses, task.DefaultWorkflow when nil
+	Notifier Notifier
+	State    *State
+	Interval time.Duration    // Time between checks
+	Reminder time.Duration    // Reminder offset of tasks without their own
+	Now      func() time.Time // Clock, time.Now when nil
+
+	tasks  []task.Task
+	loaded bool
+	sum    [sha256.Size]byte // Fingerprint of tasks
+}
+
+// Run checks the tasks until ctx is cancelled. Failing checks are logged and
+// retried at the next interval.
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Check(ctx); err != nil {
+			logger.Error("checking due tasks failed", zap.String("file", w.Path), zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check reads the tasks again and sends the pending
+// notifications not sent yet. A notification that fails is sent again at
+// the next check.
+func (w *Watcher) Check(ctx context.Context) error {
+	if err := w.reload(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if w.Now != nil {
+		now = w.Now()
+	}
+
+	sent := false
+	defer func() {
+		if sent {
+			if err := w.State.Save(); err != nil {
+				logger.Error("saving notification state failed", zap.Error(err))
+			}
+		}
+	}()
+
+	workflow := w.Workflow
+	if workflow == nil {
+		workflow = task.DefaultWorkflow
+	}
+	for _, e := range Pending(w.tasks, workflow, now, w.Reminder) {
+		if w.State.Done(e) {
+			continue
+		}
+		if err := w.Notifier.Notify(ctx, e, now); err != nil {
+			return err
+		}
+		w.State.Mark(e, now)
+		sent = true
+	}
+	return nil
+}
+
+// reload reads the tasks from the store. The store is read on every check
+// rather than watching the tasks file, whose size and modification time miss
+// the changes sqlite writes to its write-ahead log.
+func (w *Watcher) reload() error {
+	tasks, err := w.Load()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(tasks)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	if w.loaded && sum == w.sum {
+		return nil
+	}
+
+	w.tasks, w.loaded, w.sum = tasks, true, sum
+	logger.Debug("tasks reloaded", zap.String("file", w.Path), zap.Int("count", len(tasks)))
+
+	w.State.Prune(tasks)
+	return nil
+}
@@ -0,0 +1,127 @@
+package render
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+	"gopkg.in/yaml.v3"
+)
+
+// jsonRenderer writes tasks as indented JSON, a list as an array
+type jsonRenderer struct{}
+
+func (jsonRenderer) Tasks(w io.Writer, tasks []task.Task) error {
+	if tasks == nil {
+		tasks = []task.Task{}
+	}
+	return writeJSON(w, tasks)
+}
+
+func (jsonRenderer) Task(w io.Writer, t task.Task) error {
+	return writeJSON(w, t)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// yamlRenderer writes tasks as YAML using the same keys as the JSON output
+type yamlRenderer struct{}
+
+func (yamlRenderer) Tasks(w io.Writer, tasks []task.Task) error {
+	if tasks == nil {
+		tasks = []task.Task{}
+	}
+	return writeYAML(w, tasks)
+}
+
+func (yamlRenderer) Task(w io.Writer, t task.Task) error {
+	return writeYAML(w, t)
+}
+
+// writeYAML encodes v through JSON so that the YAML keys match the JSON tags
+// of the task fields and keep their order
+func writeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	// JSON is valid YAML; decoding it into a node keeps the key order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	resetStyle(&node)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// resetStyle drops the JSON flow and quoting styles so the output is block YAML
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// csvHeader lists the columns of the CSV output
+var csvHeader = []string{"id", "title", "description", "status", "priority", "due", "tags", "parent", "depends_on", "time_spent", "estimate", "recurrence", "created", "updated"}
+
+// csvRenderer writes tasks as CSV with a header row
+type csvRenderer struct {
+	now time.Time // Reference time for running timers
+}
+
+func (r csvRenderer) Tasks(w io.Writer, tasks []task.Task) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		due, recurrence := "", ""
+		if t.DueAt != nil {
+			due = t.DueAt.Format(time.RFC3339)
+		}
+		if t.Recurrence != nil {
+			recurrence = t.Recurrence.String()
+		}
+		record := []string{
+			t.ID, t.Title, t.Description, string(t.Status), string(t.Priority), due,
+			strings.Join(t.Tags, ","), t.ParentID, strings.Join(t.DependsOn, ","),
+			csvDuration(t.TimeSpent(r.now)), csvDuration(t.Estimate), recurrence, t.CreatedAt.Format(time.RFC3339), t.UpdatedAt.Format(time.RFC3339),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("error writing CSV: %w", err)
+	}
+	return nil
+}
+
+func (r csvRenderer) Task(w io.Writer, t task.Task) error {
+	return r.Tasks(w, []task.Task{t})
+}
+
+// csvDuration writes d in the Go duration syntax, e.g. "1h30m0s", or nothing when zero
+func csvDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,106 @@
+// Package render writes tasks in the output formats selected with --output
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+)
+
+// Format is an output format
+type Format string
+
+const (
+	FormatTable    Format = "table"    // Aligned columns, truncated to the terminal width
+	FormatWide     Format = "wide"     // All columns, never truncated
+	FormatJSON     Format = "json"     // Indented JSON
+	FormatYAML     Format = "yaml"     // YAML documents
+	FormatCSV      Format = "csv"      // CSV with a header row
+	FormatTemplate Format = "template" // Go template executed for every task
+)
+
+// Formats lists the accepted formats, used in help and error messages
+var Formats = []Format{FormatTable, FormatWide, FormatJSON, FormatYAML, FormatCSV, FormatTemplate}
+
+// Renderer writes tasks in one output format
+type Renderer interface {
+	// Tasks writes a list of tasks
+	Tasks(w io.Writer, tasks []task.Task) error
+	// Task writes a single task
+	Task(w io.Writer, t task.Task) error
+}
+
+// Options tunes the human readable formats
+type Options struct {
+	Width      int       // Terminal width in columns, 0 when unknown
+	Color      bool      // Whether ANSI colors may be used
+	DateFormat string    // Layout of due dates
+	Now        time.Time // Reference time for overdue detection
+
+	// Workflow tells which statuses are done, task.DefaultWorkflow when nil
+	Workflow *task.Workflow
+
+	// Progress is the roll-up of the subtasks of every parent task, keyed by
+	// task ID, see task.RollUp
+	Progress map[string]task.Progress
+
+	// Blocked lists the unfinished dependencies of every blocked task, keyed
+	// by task ID, see task.BlockedBy
+	Blocked map[string][]string
+}
+
+// New returns the renderer for spec, which is a format name or
+// "template=<go-template>"
+func New(spec string, opts Options) (Renderer, error) {
+	if opts.DateFormat == "" {
+		opts.DateFormat = time.RFC3339
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.Workflow == nil {
+		opts.Workflow = task.DefaultWorkflow
+	}
+
+	name, arg, hasArg := strings.Cut(spec, "=")
+	switch Format(strings.ToLower(name)) {
+	case FormatTable:
+		return &tableRenderer{opts: opts}, nil
+	case FormatWide:
+		return &tableRenderer{opts: opts, wide: true}, nil
+	case FormatJSON:
+		return jsonRenderer{}, nil
+	case FormatYAML:
+		return yamlRenderer{}, nil
+	case FormatCSV:
+		return csvRenderer{now: opts.Now}, nil
+	case FormatTemplate:
+		if !hasArg || arg == "" {
+			return nil, invalid("template format requires a template, e.g. --output 'template={{.ID}} {{.Title}}'")
+		}
+		return newTemplateRenderer(arg)
+	}
+
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return nil, invalid(fmt.Sprintf("invalid output format: %q. Use one of: %s", spec, strings.Join(names, ", ")))
+}
+
+// IsHuman reports whether spec selects a format meant to be read by people,
+// as opposed to parsed by scripts
+func IsHuman(spec string) bool {
+	switch Format(strings.ToLower(spec)) {
+	case FormatTable, FormatWide:
+		return true
+	}
+	return false
+}
+
+func invalid(message string) error {
+	return &task.ValidationError{Field: "output", Message: message}
+}
@@ -0,0 +1,232 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+)
+
+func testTasks() []task.Task {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC)
+	return []task.Task{
+		{ID: "aaa11111", Title: "Deploy the API to the production cluster", Description: "Roll out, then verify",
+			Status: task.StatusTodo, Priority: task.PriorityHigh, DueAt: &due, Tags: []string{"ops", "api"},
+			CreatedAt: created, UpdatedAt: created},
+		{ID: "bbb22222", Title: "Write docs", Status: task.StatusDone, Priority: task.PriorityLow,
+			CreatedAt: created, UpdatedAt: created},
+	}
+}
+
+func renderTasks(t *testing.T, spec string, opts Options) string {
+	t.Helper()
+	r, err := New(spec, opts)
+	if err != nil {
+		t.Fatalf("New(%q) failed: %v", spec, err)
+	}
+	var buf bytes.Buffer
+	if err := r.Tasks(&buf, testTasks()); err != nil {
+		t.Fatalf("Rendering %q failed: %v", spec, err)
+	}
+	return buf.String()
+}
+
+func TestNew_InvalidFormat(t *testing.T) {
+	for _, spec := range []string{"xml", "template", "template={{.ID"} {
+		if _, err := New(spec, Options{}); !errors.Is(err, task.ErrValidation) {
+			t.Errorf("Expected validation error for %q, got %v", spec, err)
+		}
+	}
+}
+
+func TestTable(t *testing.T) {
+	now := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	out := renderTasks(t, "table", Options{Now: now})
+
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a header and 2 rows, got:\n%s", out)
+	}
+	if !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[0], "TITLE") {
+		t.Errorf("Unexpected header %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "(OVERDUE)") {
+		t.Errorf("Expected the overdue task to be flagged, got %q", lines[1])
+	}
+	if strings.Contains(lines[2], "(OVERDUE)") {
+		t.Errorf("Did not expect the done task to be flagged, got %q", lines[2])
+	}
+}
+
+func TestTable_FitsWidth(t *testing.T) {
+	out := renderTasks(t, "table", Options{Width: 90})
+
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		if n := len([]rune(line)); n > 90 {
+			t.Errorf("Line is %d columns wide, expected at most 90: %q", n, line)
+		}
+	}
+	if !strings.Contains(out, "…") {
+		t.Errorf("Expected the title to be truncated, got:\n%s", out)
+	}
+
+	if wide := renderTasks(t, "wide", Options{Width: 90}); !strings.Contains(wide, "Deploy the API to the production cluster") {
+		t.Errorf("Expected the wide format not to truncate, got:\n%s", wide)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var tasks []task.Task
+	if err := json.Unmarshal([]byte(renderTasks(t, "json", Options{})), &tasks); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].ID != "aaa11111" {
+		t.Errorf("Unexpected tasks %+v", tasks)
+	}
+}
+
+func TestYAML(t *testing.T) {
+	out := renderTasks(t, "yaml", Options{})
+
+	for _, want := range []string{"- id: aaa11111", "  due_at: \"2026-03-02T17:00:00Z\"", "    - ops"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected YAML to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestCSV(t *testing.T) {
+	out := renderTasks(t, "csv", Options{})
+
+	expected := "id,title,description,status,priority,due,tags,parent,depends_on,time_spent,estimate,recurrence,created,updated\n" +
+		"aaa11111,Deploy the API to the production cluster,\"Roll out, then verify\",TODO,HIGH,2026-03-02T17:00:00Z,\"ops,api\",,,,,,2026-03-01T09:00:00Z,2026-03-01T09:00:00Z\n" +
+		"bbb22222,Write docs,,DONE,LOW,,,,,,,,2026-03-01T09:00:00Z,2026-03-01T09:00:00Z\n"
+	if out != expected {
+		t.Errorf("Unexpected CSV:\n%s", out)
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	out := renderTasks(t, `template={{.ID}} {{lower .Status}} {{join .Tags ";"}} {{date "2006-01-02" .DueAt}}`, Options{})
+
+	expected := "aaa11111 todo ops;api 2026-03-02\nbbb22222 done  \n"
+	if out != expected {
+		t.Errorf("Expected %q, got %q", expected, out)
+	}
+}
+
+func TestTree(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	tasks := []task.Task{
+		{ID: "1", Title: "Release", Status: task.StatusTodo, CreatedAt: created},
+		{ID: "2", Title: "Build", ParentID: "1", Status: task.StatusDone, CreatedAt: created},
+		{ID: "3", Title: "Other", Status: task.StatusTodo, CreatedAt: created},
+		{ID: "4", Title: "Test", ParentID: "1", Status: task.StatusTodo, CreatedAt: created},
+		{ID: "5", Title: "Unit", ParentID: "4", Status: task.StatusTodo, CreatedAt: created},
+	}
+	progress := task.RollUp(tasks, task.DefaultWorkflow)
+
+	r, err := New("table", Options{Progress: progress})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := r.(TreeRenderer).Tree(&buf, tasks); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	column := strings.Index(lines[0], "TITLE")
+	var titles []string
+	for _, line := range lines[1:] {
+		titles = append(titles, strings.TrimRight(line[column:], " "))
+	}
+	expected := []string{"Release (1/3 done)", "├─ Build", "└─ Test (0/1 done)", "   └─ Unit", "Other"}
+	if strings.Join(titles, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected titles:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(titles, "\n"))
+	}
+
+	if ids := TreeOrder(tasks); ids[2].ID != "4" || ids[3].ID != "5" {
+		t.Errorf("Expected subtasks right after their parent, got %v", ids)
+	}
+}
+
+func TestReport(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	r := task.Report{
+		Period: task.PeriodWeek, Since: start, Until: start.AddDate(0, 0, 6),
+		Periods:    []task.PeriodSummary{{Start: start, Created: 3, Completed: 2, LeadTime: 52 * time.Hour, CycleTime: 90 * time.Minute}},
+		Total:      task.PeriodSummary{Start: start, Created: 3, Completed: 2, LeadTime: 52 * time.Hour, CycleTime: 90 * time.Minute},
+		TimePerTag: []task.TagTime{{Tag: "api", Spent: 2 * time.Hour}},
+	}
+
+	var text bytes.Buffer
+	if err := Report(&text, "table", r); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Weekly report from 2026-03-02 to 2026-03-08", "WEEK OF", "2d4h", "1h30m", "TOTAL", "api  2h"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", want, text.String())
+		}
+	}
+
+	var out bytes.Buffer
+	if err := Report(&out, "json", r); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Total struct {
+			LeadTimeSeconds int64 `json:"lead_time_seconds"`
+		} `json:"total"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || decoded.Total.LeadTimeSeconds != 52*3600 {
+		t.Errorf("Unexpected JSON report %s (%v)", out.String(), err)
+	}
+
+	if err := Report(&out, "csv", r); !errors.Is(err, task.ErrValidation) {
+		t.Errorf("Expected a validation error for CSV reports, got %v", err)
+	}
+}
+
+func TestTags(t *testing.T) {
+	counts := []task.TagCount{{Tag: "backend", Count: 3}, {Tag: "ui", Count: 1}}
+
+	var text bytes.Buffer
+	if err := Tags(&text, "table", counts); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), "backend") || !strings.Contains(text.String(), "3") {
+		t.Errorf("Unexpected table output:\n%s", text.String())
+	}
+
+	var out bytes.Buffer
+	if err := Tags(&out, "json", counts); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []task.TagCount
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || len(decoded) != 2 || decoded[0] != counts[0] {
+		t.Errorf("Unexpected JSON output %s (%v)", out.String(), err)
+	}
+
+	out.Reset()
+	if err := Tags(&out, "csv", counts); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "tag,count\nbackend,3\nui,1\n" {
+		t.Errorf("Unexpected CSV output %q", out.String())
+	}
+
+	out.Reset()
+	if err := Tags(&out, "json", nil); err != nil || strings.TrimSpace(out.String()) != "[]" {
+		t.Errorf("Expected an empty JSON array without tags, got %q (%v)", out.String(), err)
+	}
+
+	if err := Tags(&out, "template={{.Tag}}", counts); !errors.Is(err, task.ErrValidation) {
+		t.Errorf("Expected a validation error for templates, got %v", err)
+	}
+}
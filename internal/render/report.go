@@ -0,0 +1,133 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+)
+
+// reportDateLayout is the layout of the dates in text reports
+const reportDateLayout = "2006-01-02"
+
+// reportJSON is the machine readable form of a task.Report. Durations are
+// whole seconds so scripts don't have to parse Go durations.
+type reportJSON struct {
+	Period     task.Period         `json:"period"`
+	Since      time.Time           `json:"since"`
+	Until      time.Time           `json:"until"`
+	Periods    []periodSummaryJSON `json:"periods"`
+	Total      periodSummaryJSON   `json:"total"`
+	TimePerTag []tagTimeJSON       `json:"time_per_tag"`
+}
+
+type periodSummaryJSON struct {
+	Start            time.Time `json:"start"`
+	Created          int       `json:"created"`
+	Completed        int       `json:"completed"`
+	LeadTimeSeconds  int64     `json:"lead_time_seconds"`
+	CycleTimeSeconds int64     `json:"cycle_time_seconds"`
+}
+
+type tagTimeJSON struct {
+	Tag          string `json:"tag"`
+	SpentSeconds int64  `json:"spent_seconds"`
+}
+
+// Report writes r in the format selected by spec: a text table for the human
+// readable formats, or JSON or YAML
+func Report(w io.Writer, spec string, r task.Report) error {
+	switch Format(strings.ToLower(spec)) {
+	case FormatTable, FormatWide:
+		return writeReportText(w, r)
+	case FormatJSON:
+		return writeJSON(w, newReportJSON(r))
+	case FormatYAML:
+		return writeYAML(w, newReportJSON(r))
+	}
+	return invalid(fmt.Sprintf("invalid output format for reports: %q. Use one of: %s, %s, %s", spec, FormatTable, FormatJSON, FormatYAML))
+}
+
+func newReportJSON(r task.Report) reportJSON {
+	summary := func(s task.PeriodSummary) periodSummaryJSON {
+		return periodSummaryJSON{
+			Start:            s.Start,
+			Created:          s.Created,
+			Completed:        s.Completed,
+			LeadTimeSeconds:  int64(s.LeadTime.Seconds()),
+			CycleTimeSeconds: int64(s.CycleTime.Seconds()),
+		}
+	}
+
+	out := reportJSON{
+		Period:     r.Period,
+		Since:      r.Since,
+		Until:      r.Until,
+		Periods:    []periodSummaryJSON{},
+		Total:      summary(r.Total),
+		TimePerTag: []tagTimeJSON{},
+	}
+	for _, s := range r.Periods {
+		out.Periods = append(out.Periods, summary(s))
+	}
+	for _, tt := range r.TimePerTag {
+		out.TimePerTag = append(out.TimePerTag, tagTimeJSON{Tag: tt.Tag, SpentSeconds: int64(tt.Spent.Seconds())})
+	}
+	return out
+}
+
+func writeReportText(w io.Writer, r task.Report) error {
+	heading, column := "Daily", "DAY"
+	if r.Period == task.PeriodWeek {
+		heading, column = "Weekly", "WEEK OF"
+	}
+	fmt.Fprintf(w, "%s report from %s to %s\n\n", heading, r.Since.Format(reportDateLayout), r.Until.Format(reportDateLayout))
+
+	tw := tabwriter.NewWriter(w, 0, 0, columnGap, ' ', 0)
+	fmt.Fprintf(tw, "%s\tCREATED\tCOMPLETED\tLEAD TIME\tCYCLE TIME\n", column)
+	row := func(label string, s task.PeriodSummary) {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\n", label, s.Created, s.Completed, formatSpan(s.LeadTime), formatSpan(s.CycleTime))
+	}
+	for _, s := range r.Periods {
+		row(s.Start.Format(reportDateLayout), s)
+	}
+	row("TOTAL", r.Total)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+	if len(r.TimePerTag) == 0 {
+		_, err := fmt.Fprintln(w, "No time logged.")
+		return err
+	}
+	tw = tabwriter.NewWriter(w, 0, 0, columnGap, ' ', 0)
+	fmt.Fprintln(tw, "TAG\tTIME SPENT")
+	for _, tt := range r.TimePerTag {
+		fmt.Fprintf(tw, "%s\t%s\n", tt.Tag, task.FormatDuration(tt.Spent))
+	}
+	return tw.Flush()
+}
+
+// formatSpan writes lead and cycle times, counting days past 24 hours,
+// e.g. "2d4h", or "-" when there is none
+func formatSpan(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	days := int(d / (24 * time.Hour))
+	if days == 0 {
+		return task.FormatDuration(d)
+	}
+	rest := (d % (24 * time.Hour)).Round(time.Hour)
+	if rest == 24*time.Hour {
+		days, rest = days+1, 0
+	}
+	if rest == 0 {
+		return fmt.Sprintf("%dd", days)
+	}
+	return fmt.Sprintf("%dd%dh", days, int(rest.Hours()))
+}
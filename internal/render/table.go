@@ -0,0 +1,261 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+)
+
+const (
+	columnGap       = 2  // Spaces between table columns
+	minTitleWidth   = 10 // Narrowest the title column is truncated to
+	colorOverdue    = "\033[31m"
+	colorReset      = "\033[0m"
+	ellipsis        = "…"
+	overdueSuffix   = " (OVERDUE)"
+	blockedSuffix   = " (BLOCKED)"
+	runningMark     = "*"
+	detailSeparator = "------"
+)
+
+// tableRenderer writes tasks as aligned columns, or a single task as a block
+type tableRenderer struct {
+	opts   Options
+	wide   bool
+	prefix map[string]string // Tree branches drawn before titles, see Tree
+}
+
+// column is a table column; the title column shrinks to fit the terminal
+type column struct {
+	header string
+	value  func(t task.Task) string
+	indent func(t task.Task) string // Kept as is before the value, which has its spaces collapsed
+	shrink bool
+}
+
+func (r *tableRenderer) columns() []column {
+	cols := []column{
+		{header: "ID", value: func(t task.Task) string { return t.ID }},
+		{header: "STATUS", value: func(t task.Task) string {
+			if len(r.opts.Blocked[t.ID]) > 0 {
+				return string(t.Status) + blockedSuffix
+			}
+			return string(t.Status)
+		}},
+		{header: "PRIORITY", value: func(t task.Task) string { return string(t.Priority) }},
+		{header: "DUE", value: func(t task.Task) string {
+			// Without colors the row cannot be highlighted, so flag the date instead
+			if t.IsOverdue(r.opts.Now, r.opts.Workflow) && !r.opts.Color {
+				return r.due(t) + overdueSuffix
+			}
+			return r.due(t)
+		}},
+		{header: "TIME", value: r.spent},
+	}
+	if r.wide {
+		cols = append(cols, column{header: "REMAINING", value: func(t task.Task) string {
+			if remaining, ok := t.Remaining(r.opts.Now); ok {
+				return task.FormatDuration(remaining)
+			}
+			return ""
+		}})
+	}
+	cols = append(cols, column{header: "TITLE", value: r.title, indent: func(t task.Task) string { return r.prefix[t.ID] }, shrink: !r.wide})
+	if r.wide {
+		cols = append(cols, column{header: "DESCRIPTION", value: func(t task.Task) string { return t.Description }})
+	}
+	cols = append(cols, column{header: "TAGS", value: func(t task.Task) string { return strings.Join(t.Tags, ",") }})
+	if r.wide {
+		cols = append(cols,
+			column{header: "CREATED", value: func(t task.Task) string { return t.CreatedAt.Format(time.RFC3339) }},
+			column{header: "UPDATED", value: func(t task.Task) string { return t.UpdatedAt.Format(time.RFC3339) }},
+		)
+	}
+	return cols
+}
+
+// title returns the title of t with the progress of its subtasks
+func (r *tableRenderer) title(t task.Task) string {
+	title := t.Title
+	if p, ok := r.opts.Progress[t.ID]; ok {
+		title += " (" + p.String() + ")"
+	}
+	return title
+}
+
+// spent returns the work spent on t and its estimate, e.g. "1h30m/4h", with
+// a mark while its timer runs
+func (r *tableRenderer) spent(t task.Task) string {
+	if len(t.Work) == 0 && t.Estimate == 0 {
+		return ""
+	}
+	spent := task.FormatDuration(t.TimeSpent(r.opts.Now))
+	if t.Running() != nil {
+		spent += runningMark
+	}
+	if t.Estimate > 0 {
+		spent += "/" + task.FormatDuration(t.Estimate)
+	}
+	return spent
+}
+
+func (r *tableRenderer) due(t task.Task) string {
+	if t.DueAt == nil {
+		return ""
+	}
+	return t.DueAt.Format(r.opts.DateFormat)
+}
+
+// Tasks writes one row per task under a header row
+func (r *tableRenderer) Tasks(w io.Writer, tasks []task.Task) error {
+	if len(tasks) == 0 {
+		_, err := fmt.Fprintln(w, "No tasks found")
+		return err
+	}
+
+	cols := r.columns()
+	cells := make([][]string, len(tasks))
+	widths := make([]int, len(cols))
+	for i, c := range cols {
+		widths[i] = utf8.RuneCountInString(c.header)
+	}
+	for row, t := range tasks {
+		cells[row] = make([]string, len(cols))
+		for i, c := range cols {
+			value := strings.Join(strings.Fields(c.value(t)), " ")
+			if c.indent != nil {
+				value = c.indent(t) + value
+			}
+			cells[row][i] = value
+			widths[i] = max(widths[i], utf8.RuneCountInString(value))
+		}
+	}
+	r.fit(cols, widths)
+
+	headers := make([]string, len(cols))
+	for i, c := range cols {
+		headers[i] = c.header
+	}
+	if err := r.writeRow(w, widths, headers, false); err != nil {
+		return err
+	}
+	for row, t := range tasks {
+		if err := r.writeRow(w, widths, cells[row], t.IsOverdue(r.opts.Now, r.opts.Workflow)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fit shrinks the shrinkable columns so that rows fit in the terminal width
+func (r *tableRenderer) fit(cols []column, widths []int) {
+	if r.opts.Width <= 0 {
+		return
+	}
+
+	total := columnGap * (len(cols) - 1)
+	for _, width := range widths {
+		total += width
+	}
+	for i, c := range cols {
+		if total <= r.opts.Width {
+			return
+		}
+		if !c.shrink || widths[i] <= minTitleWidth {
+			continue
+		}
+		shrunk := max(minTitleWidth, widths[i]-(total-r.opts.Width))
+		total -= widths[i] - shrunk
+		widths[i] = shrunk
+	}
+}
+
+func (r *tableRenderer) writeRow(w io.Writer, widths []int, cells []string, overdue bool) error {
+	var b strings.Builder
+	for i, cell := range cells {
+		cell = truncate(cell, widths[i])
+		last := i == len(cells)-1
+		if !last {
+			cell += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+columnGap)
+		}
+		b.WriteString(cell)
+	}
+	line := strings.TrimRight(b.String(), " ")
+	if overdue && r.opts.Color {
+		line = colorOverdue + line + colorReset
+	}
+	_, err := fmt.Fprintln(w, line)
+	return err
+}
+
+// truncate shortens s to width runes, marking the cut with an ellipsis
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + ellipsis
+}
+
+// Task writes every field of t, one per line
+func (r *tableRenderer) Task(w io.Writer, t task.Task) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\nID: %s\nTitle: %s\nDescription: %s\nStatus: %s\nPriority: %s\n",
+		detailSeparator, t.ID, t.Title, t.Description, t.Status, t.Priority)
+	if t.DueAt != nil {
+		due := "Due: " + r.due(t)
+		if t.IsOverdue(r.opts.Now, r.opts.Workflow) {
+			due += overdueSuffix
+			if r.opts.Color {
+				due = colorOverdue + due + colorReset
+			}
+		}
+		b.WriteString(due + "\n")
+	}
+	if t.Reminder > 0 {
+		fmt.Fprintf(&b, "Reminder: %s before due\n", task.FormatDuration(t.Reminder))
+	}
+	if len(t.Tags) > 0 {
+		fmt.Fprintf(&b, "Tags: %s\n", strings.Join(t.Tags, ", "))
+	}
+	if t.ParentID != "" {
+		fmt.Fprintf(&b, "Parent: %s\n", t.ParentID)
+	}
+	if p, ok := r.opts.Progress[t.ID]; ok {
+		fmt.Fprintf(&b, "Subtasks: %s\n", p)
+	}
+	if len(t.Work) > 0 || t.Estimate > 0 {
+		spent := task.FormatDuration(t.TimeSpent(r.opts.Now))
+		if running := t.Running(); running != nil {
+			spent += fmt.Sprintf(" (running since %s)", running.Start.Format(time.RFC3339))
+		}
+		fmt.Fprintf(&b, "Time spent: %s\n", spent)
+	}
+	if remaining, ok := t.Remaining(r.opts.Now); ok {
+		fmt.Fprintf(&b, "Estimate: %s\nRemaining: %s\n", task.FormatDuration(t.Estimate), task.FormatDuration(remaining))
+	}
+	if len(t.DependsOn) > 0 {
+		fmt.Fprintf(&b, "Depends on: %s\n", strings.Join(t.DependsOn, ", "))
+	}
+	if blockers := r.opts.Blocked[t.ID]; len(blockers) > 0 {
+		fmt.Fprintf(&b, "Blocked by: %s\n", strings.Join(blockers, ", "))
+	}
+	if t.Recurrence != nil {
+		fmt.Fprintf(&b, "Repeats: %s\n", t.Recurrence.Describe())
+	}
+	if t.PreviousID != "" {
+		fmt.Fprintf(&b, "Previous occurrence: %s\n", t.PreviousID)
+	}
+	if t.NextID != "" {
+		fmt.Fprintf(&b, "Next occurrence: %s\n", t.NextID)
+	}
+	fmt.Fprintf(&b, "Created: %s\nUpdated: %s\n%s\n",
+		t.CreatedAt.Format(time.RFC3339), t.UpdatedAt.Format(time.RFC3339), detailSeparator)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
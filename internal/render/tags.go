@@ -0,0 +1,50 @@
+package render
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+)
+
+// Tags writes the tags in use with the number of tasks carrying each, in the
+// format selected by spec: aligned columns for the human readable formats, or
+// JSON, YAML or CSV
+func Tags(w io.Writer, spec string, counts []task.TagCount) error {
+	if counts == nil {
+		counts = []task.TagCount{}
+	}
+
+	switch Format(strings.ToLower(spec)) {
+	case FormatTable, FormatWide:
+		if len(counts) == 0 {
+			_, err := fmt.Fprintln(w, "No tags found")
+			return err
+		}
+		for _, c := range counts {
+			if _, err := fmt.Fprintf(w, "%-20s %d\n", c.Tag, c.Count); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatJSON:
+		return writeJSON(w, counts)
+	case FormatYAML:
+		return writeYAML(w, counts)
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"tag", "count"})
+		for _, c := range counts {
+			cw.Write([]string{c.Tag, strconv.Itoa(c.Count)})
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return fmt.Errorf("error writing CSV: %w", err)
+		}
+		return nil
+	}
+	return invalid(fmt.Sprintf("invalid output format for tags: %q. Use one of: %s, %s, %s, %s", spec, FormatTable, FormatJSON, FormatYAML, FormatCSV))
+}
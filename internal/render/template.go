@@ -0,0 +1,60 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+)
+
+// templateFuncs are the functions available to --output templates besides the builtins
+var templateFuncs = template.FuncMap{
+	"join":  func(list []string, sep string) string { return strings.Join(list, sep) },
+	"upper": func(v any) string { return strings.ToUpper(fmt.Sprint(v)) },
+	"lower": func(v any) string { return strings.ToLower(fmt.Sprint(v)) },
+	// date formats a time or a due date (empty when unset) with a Go layout
+	"date": func(layout string, t any) string {
+		switch v := t.(type) {
+		case time.Time:
+			return v.Format(layout)
+		case *time.Time:
+			if v != nil {
+				return v.Format(layout)
+			}
+		}
+		return ""
+	},
+}
+
+// templateRenderer executes a Go template once for every task, each on its own line
+type templateRenderer struct {
+	tmpl *template.Template
+}
+
+func newTemplateRenderer(text string) (*templateRenderer, error) {
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, invalid(fmt.Sprintf("invalid output template: %v", err))
+	}
+	return &templateRenderer{tmpl: tmpl}, nil
+}
+
+func (r *templateRenderer) Tasks(w io.Writer, tasks []task.Task) error {
+	for _, t := range tasks {
+		if err := r.Task(w, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *templateRenderer) Task(w io.Writer, t task.Task) error {
+	if err := r.tmpl.Execute(w, t); err != nil {
+		return fmt.Errorf("error executing output template: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
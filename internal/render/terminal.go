@@ -0,0 +1,29 @@
+package render
+
+import (
+	"io"
+	"os"
+	"strconv"
+)
+
+// IsTerminal reports whether w writes to a terminal
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// TerminalWidth returns the width in columns of the terminal w writes to.
+// $COLUMNS takes precedence; 0 means the width is unknown.
+func TerminalWidth(w io.Writer) int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	if f, ok := w.(*os.File); ok && IsTerminal(w) {
+		return terminalWidth(f)
+	}
+	return 0
+}
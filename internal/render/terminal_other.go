@@ -0,0 +1,10 @@
+//go:build !unix
+
+package render
+
+import "os"
+
+// terminalWidth is not implemented on this platform; tables are not truncated
+func terminalWidth(f *os.File) int {
+	return 0
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package render
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func terminalWidth(f *os.File) int {
+	ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0
+	}
+	return int(ws.Col)
+}
@@ -0,0 +1,93 @@
+package render
+
+import (
+	"io"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/task"
+)
+
+// Branches drawn before the titles of subtasks in a tree
+const (
+	branchMiddle = "├─ "
+	branchLast   = "└─ "
+	branchPipe   = "│  "
+	branchSpace  = "   "
+)
+
+// TreeRenderer is implemented by renderers able to draw the parent/child
+// hierarchy of tasks
+type TreeRenderer interface {
+	// Tree writes tasks with every subtask below its parent
+	Tree(w io.Writer, tasks []task.Task) error
+}
+
+// Tree writes tasks as a table with subtasks indented below their parent
+func (r *tableRenderer) Tree(w io.Writer, tasks []task.Task) error {
+	ordered, prefix := tree(tasks)
+	tr := *r
+	tr.prefix = prefix
+	return tr.Tasks(w, ordered)
+}
+
+// TreeOrder returns tasks with every subtask right after its parent, for the
+// renderers that cannot draw the tree itself
+func TreeOrder(tasks []task.Task) []task.Task {
+	ordered, _ := tree(tasks)
+	return ordered
+}
+
+// tree orders tasks depth first, keeping the relative order of siblings, and
+// returns the branch to draw before the title of each task. Tasks whose
+// parent is not among tasks, for instance because it was filtered out, are
+// shown as roots.
+func tree(tasks []task.Task) ([]task.Task, map[string]string) {
+	present := make(map[string]bool, len(tasks))
+	for _, t := range tasks {
+		present[t.ID] = true
+	}
+	children := make(map[string][]task.Task)
+	var roots []task.Task
+	for _, t := range tasks {
+		if t.ParentID != "" && present[t.ParentID] && t.ParentID != t.ID {
+			children[t.ParentID] = append(children[t.ParentID], t)
+		} else {
+			roots = append(roots, t)
+		}
+	}
+
+	ordered := make([]task.Task, 0, len(tasks))
+	prefix := make(map[string]string, len(tasks))
+	visited := make(map[string]bool, len(tasks))
+	var walk func(t task.Task, indent, branch string)
+	walk = func(t task.Task, indent, branch string) {
+		if visited[t.ID] {
+			return
+		}
+		visited[t.ID] = true
+		ordered = append(ordered, t)
+		prefix[t.ID] = indent + branch
+
+		switch branch {
+		case branchMiddle:
+			indent += branchPipe
+		case branchLast:
+			indent += branchSpace
+		}
+		kids := children[t.ID]
+		for i, child := range kids {
+			next := branchMiddle
+			if i == len(kids)-1 {
+				next = branchLast
+			}
+			walk(child, indent, next)
+		}
+	}
+	for _, t := range roots {
+		walk(t, "", "")
+	}
+	// Tasks in a cycle of a hand edited file have no root; show them anyway
+	for _, t := range tasks {
+		walk(t, "", "")
+	}
+	return ordered, prefix
+}
@@ -0,0 +1,266 @@
+package task
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/fsutil"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/logger"
+	"go.uber.org/zap"
+)
+
+// backupTimeFormat is embedded in backup file names; it sorts lexically
+const backupTimeFormat = "20060102T150405.000Z"
+
+// ErrBackupNotFound is returned when restoring a backup that doesn't exist
+var ErrBackupNotFound = errors.New("backup not found")
+
+// Backup is a JSON snapshot of all tasks stored in the backup directory
+type Backup struct {
+	Name      string    // File name, used to refer to the backup
+	Path      string    // Full path of the backup file
+	CreatedAt time.Time // When the snapshot was taken
+	Size      int64     // File size in bytes
+}
+
+// backupBase is the prefix shared by the backups of this storage, e.g. "tasks"
+func (ts *TaskStorage) backupBase() string {
+	base := filepath.Base(ts.cfg.Storage.FilePath)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// CreateBackup writes a snapshot of all tasks to the backup directory and
+// applies the retention policy to older backups.
+func (ts *TaskStorage) CreateBackup() (Backup, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	var backup Backup
+	err := ts.store.Transaction(func(tx Store) error {
+		var err error
+		backup, err = ts.createBackup(tx, time.Now())
+		return err
+	})
+	return backup, err
+}
+
+// createBackup snapshots the tasks in tx, so that the backup matches the
+// stored tasks while the store is locked rather than the last ones read
+func (ts *TaskStorage) createBackup(tx Store, now time.Time) (Backup, error) {
+	dir := ts.cfg.Storage.BackupDir
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return Backup{}, fmt.Errorf("error creating backup directory: %w", err)
+	}
+
+	tasks, err := tx.List()
+	if err != nil {
+		return Backup{}, err
+	}
+	data, err := json.MarshalIndent(tasks, "", "  ")
+	if err != nil {
+		return Backup{}, fmt.Errorf("error serializing tasks: %w", err)
+	}
+
+	now = now.UTC().Truncate(time.Millisecond)
+	name := fmt.Sprintf("%s-%s.json", ts.backupBase(), now.Format(backupTimeFormat))
+	path := filepath.Join(dir, name)
+	if err := fsutil.WriteFile(path, data, 0644); err != nil {
+		return Backup{}, fmt.Errorf("error writing backup: %w", err)
+	}
+
+	if err := ts.pruneBackups(); err != nil {
+		return Backup{}, err
+	}
+
+	return Backup{Name: name, Path: path, CreatedAt: now, Size: int64(len(data))}, nil
+}
+
+// ListBackups returns the backups of this storage, newest first
+func (ts *TaskStorage) ListBackups() ([]Backup, error) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	return ts.listBackups()
+}
+
+func (ts *TaskStorage) listBackups() ([]Backup, error) {
+	prefix := ts.backupBase() + "-"
+	entries, err := os.ReadDir(ts.cfg.Storage.BackupDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading backup directory: %w", err)
+	}
+
+	var backups []Backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".json")
+		created, err := time.Parse(backupTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		backups = append(backups, Backup{
+			Name:      name,
+			Path:      filepath.Join(ts.cfg.Storage.BackupDir, name),
+			CreatedAt: created,
+			Size:      info.Size(),
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+	return backups, nil
+}
+
+// RestoreBackup replaces all tasks with the content of the named backup.
+// The current tasks are backed up first so the restore can be reverted.
+func (ts *TaskStorage) RestoreBackup(name string) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if name != filepath.Base(name) {
+		return fmt.Errorf("%w: %s", ErrBackupNotFound, name)
+	}
+
+	data, err := os.ReadFile(filepath.Join(ts.cfg.Storage.BackupDir, name))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s", ErrBackupNotFound, name)
+	}
+	if err != nil {
+		return fmt.Errorf("error reading backup: %w", err)
+	}
+
+	var tasks []Task
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return fmt.Errorf("error deserializing backup %s: %w", name, err)
+	}
+	// Backups may come from older versions, as the tasks file
+	for i := range tasks {
+		tasks[i].applyDefaults()
+	}
+
+	return ts.transaction(OpRestore, func(tx Store) error {
+		if _, err := ts.createBackup(tx, time.Now()); err != nil {
+			return fmt.Errorf("error backing up current tasks: %w", err)
+		}
+
+		current, err := tx.List()
+		if err != nil {
+			return err
+		}
+		for _, t := range current {
+			if err := tx.Delete(t.ID); err != nil {
+				return err
+			}
+		}
+		for _, t := range tasks {
+			if err := tx.Put(t); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// autoBackup takes a backup when AutoBackup is enabled, there is something
+// to back up and the newest backup is older than BackupInterval. Failures are
+// logged rather than returned so they never block the change that triggered
+// them. Callers must hold ts.mu and run it within the store transaction tx.
+func (ts *TaskStorage) autoBackup(tx Store, now time.Time) {
+	if !ts.cfg.Task.AutoBackup || ts.cfg.Storage.BackupDir == "" {
+		return
+	}
+	if tasks, err := tx.List(); err != nil || len(tasks) == 0 {
+		return
+	}
+
+	backups, err := ts.listBackups()
+	if err != nil {
+		logger.Error("automatic backup failed", zap.Error(err))
+		return
+	}
+	if len(backups) > 0 && now.Sub(backups[0].CreatedAt) < ts.cfg.Task.BackupInterval {
+		return
+	}
+
+	if _, err := ts.createBackup(tx, now); err != nil {
+		logger.Error("automatic backup failed", zap.Error(err))
+	}
+}
+
+// pruneBackups deletes the backups not kept by the retention policy
+func (ts *TaskStorage) pruneBackups() error {
+	backups, err := ts.listBackups()
+	if err != nil {
+		return err
+	}
+
+	for _, b := range expiredBackups(backups, ts.cfg.Task.BackupKeepDaily, ts.cfg.Task.BackupKeepWeekly) {
+		if err := os.Remove(b.Path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error removing old backup %s: %w", b.Name, err)
+		}
+	}
+	return nil
+}
+
+// expiredBackups applies the retention policy to backups sorted newest first.
+// Backups taken within a day of the newest one are always kept; older ones
+// are thinned to the newest backup of each of the last keepDaily days and of
+// each of the last keepWeekly ISO weeks. With both limits at zero every
+// backup is kept.
+func expiredBackups(backups []Backup, keepDaily, keepWeekly int) []Backup {
+	if keepDaily <= 0 && keepWeekly <= 0 || len(backups) == 0 {
+		return nil
+	}
+
+	keep := map[string]bool{}
+	for _, b := range backups {
+		if backups[0].CreatedAt.Sub(b.CreatedAt) < 24*time.Hour {
+			keep[b.Name] = true
+		}
+	}
+
+	days := map[string]bool{}
+	weeks := map[string]bool{}
+	for _, b := range backups {
+		day := b.CreatedAt.Format("2006-01-02")
+		if !days[day] && len(days) < keepDaily {
+			days[day] = true
+			keep[b.Name] = true
+		}
+
+		year, week := b.CreatedAt.ISOWeek()
+		weekKey := fmt.Sprintf("%d-%02d", year, week)
+		if !weeks[weekKey] && len(weeks) < keepWeekly {
+			weeks[weekKey] = true
+			keep[b.Name] = true
+		}
+	}
+
+	var expired []Backup
+	for _, b := range backups {
+		if !keep[b.Name] {
+			expired = append(expired, b)
+		}
+	}
+	return expired
+}
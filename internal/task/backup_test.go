@@ -0,0 +1,181 @@
+package task
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTaskStorage_BackupAndRestore(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kept, err := ts.AddTask("Keep me", "Present in the backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backup, err := ts.CreateBackup()
+	if err != nil {
+		t.Fatalf("Unexpected error creating backup: %v", err)
+	}
+
+	if _, err := ts.AddTask("Added later", "Not in the backup"); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, err := ts.ListBackups()
+	if err != nil || len(backups) != 1 || backups[0].Name != backup.Name {
+		t.Fatalf("Expected the created backup to be listed, got %v (%v)", backups, err)
+	}
+
+	if err := ts.RestoreBackup(backup.Name); err != nil {
+		t.Fatalf("Unexpected error restoring backup: %v", err)
+	}
+	if backups, _ := ts.ListBackups(); len(backups) != 2 {
+		t.Errorf("Expected restore to back up the current tasks first, got %d backups", len(backups))
+	}
+
+	tasks := ts.ListTasks()
+	if len(tasks) != 1 || tasks[0].ID != kept.ID {
+		t.Errorf("Expected only the backed up task after restore, got %v", tasks)
+	}
+
+	if err := ts.RestoreBackup("missing.json"); !errors.Is(err, ErrBackupNotFound) {
+		t.Errorf("Expected ErrBackupNotFound, got %v", err)
+	}
+	if err := ts.RestoreBackup("../tasks.json"); !errors.Is(err, ErrBackupNotFound) {
+		t.Errorf("Expected paths outside the backup directory to be rejected, got %v", err)
+	}
+}
+
+func TestTaskStorage_AutoBackup(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	cfg.Task.BackupInterval = time.Hour
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing to back up before the first task exists
+	if _, err := ts.AddTask("First", ""); err != nil {
+		t.Fatal(err)
+	}
+	if backups, _ := ts.ListBackups(); len(backups) != 0 {
+		t.Fatalf("Expected no backup of an empty list, got %d", len(backups))
+	}
+
+	if _, err := ts.AddTask("Second", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.AddTask("Third", ""); err != nil {
+		t.Fatal(err)
+	}
+	if backups, _ := ts.ListBackups(); len(backups) != 1 {
+		t.Fatalf("Expected one backup within the interval, got %d", len(backups))
+	}
+
+	ts.store.Transaction(func(tx Store) error {
+		ts.autoBackup(tx, time.Now().Add(2*time.Hour))
+		return nil
+	})
+	if backups, _ := ts.ListBackups(); len(backups) != 2 {
+		t.Errorf("Expected a new backup once the interval elapsed, got %d", len(backups))
+	}
+
+	cfg.Task.AutoBackup = false
+	disabled, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	disabled.store.Transaction(func(tx Store) error {
+		disabled.autoBackup(tx, time.Now().Add(48*time.Hour))
+		return nil
+	})
+	if backups, _ := disabled.ListBackups(); len(backups) != 2 {
+		t.Errorf("Expected no backup with autoBackup disabled, got %d", len(backups))
+	}
+}
+
+func TestExpiredBackups(t *testing.T) {
+	// Sunday 2026-10-18, then every 12 hours back for four weeks
+	newest := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC)
+	var backups []Backup
+	for i := 0; i < 56; i++ {
+		created := newest.Add(-time.Duration(i) * 12 * time.Hour)
+		backups = append(backups, Backup{Name: created.Format(backupTimeFormat), CreatedAt: created})
+	}
+
+	expired := expiredBackups(backups, 3, 2)
+	expiredNames := map[string]bool{}
+	for _, b := range expired {
+		expiredNames[b.Name] = true
+	}
+
+	kept := len(backups) - len(expired)
+	// Everything from the last 24 hours, the newest of Oct 17 and 16 for the
+	// remaining daily slots and the newest of the previous ISO week (Sunday Oct 11)
+	if kept != 5 {
+		t.Errorf("Expected 5 backups kept, got %d", kept)
+	}
+	for _, name := range []string{"20261018T200000.000Z", "20261018T080000.000Z", "20261017T200000.000Z", "20261016T200000.000Z", "20261011T200000.000Z"} {
+		if expiredNames[name] {
+			t.Errorf("Expected backup %s to be kept", name)
+		}
+	}
+
+	if len(expiredBackups(backups, 0, 0)) != 0 {
+		t.Error("Expected retention to be disabled with zero limits")
+	}
+}
+
+func TestTaskStorage_BackupReadsStoredTasks(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process adds a task after ts last read the file
+	other, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.AddTask("From elsewhere", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	backup, err := ts.CreateBackup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(backup.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "From elsewhere") {
+		t.Errorf("Expected the backup to hold the stored tasks, got %s", data)
+	}
+
+	// Backups written by older versions get the defaults of loaded tasks
+	old := filepath.Join(cfg.Storage.BackupDir, "tasks-20250101T120000.000Z.json")
+	if err := os.WriteFile(old, []byte(`[{"id":"old1","title":"Old","status":"TODO"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.RestoreBackup(filepath.Base(old)); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := ts.GetTask("old1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Priority != PriorityMedium {
+		t.Errorf("Expected restored task to get the default priority, got %q", restored.Priority)
+	}
+}
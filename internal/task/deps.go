@@ -0,0 +1,225 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// OpDeps is the journal kind of dependency changes
+const OpDeps = "deps"
+
+// BlockedBy returns, for every task waiting on tasks not done in workflow w,
+// the IDs of those tasks, keyed by task ID. Dependencies on deleted tasks are
+// ignored.
+func BlockedBy(tasks []Task, w *Workflow) map[string][]string {
+	status := make(map[string]Status, len(tasks))
+	for _, t := range tasks {
+		status[t.ID] = t.Status
+	}
+
+	blocked := make(map[string][]string)
+	for _, t := range tasks {
+		for _, dep := range t.DependsOn {
+			if s, ok := status[dep]; ok && !w.IsDone(s) {
+				blocked[t.ID] = append(blocked[t.ID], dep)
+			}
+		}
+	}
+	return blocked
+}
+
+// BlockedBy returns the unfinished dependencies of every blocked task, see BlockedBy
+func (ts *TaskStorage) BlockedBy() map[string][]string {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	return BlockedBy(ts.tasks, ts.workflow)
+}
+
+// NextTasks returns the tasks in the initial status of workflow w (TODO by
+// default) that are not blocked, in the order they should be worked on. A task
+// inherits the priority of the most important unfinished task depending on it,
+// directly or not, so that work unblocking urgent tasks comes first. Ties are broken by the task's own priority and
+// then by age, as in SortTasks.
+func NextTasks(tasks []Task, w *Workflow) []Task {
+	blocked := BlockedBy(tasks, w)
+	dependents := make(map[string][]Task)
+	for _, t := range tasks {
+		if w.IsDone(t.Status) {
+			continue
+		}
+		for _, dep := range t.DependsOn {
+			dependents[dep] = append(dependents[dep], t)
+		}
+	}
+
+	// effective is the highest rank among id and the tasks waiting on it.
+	// path holds the tasks on the current recursion path, so that a cycle in
+	// hand-edited files ends the walk instead of looping; ranks are only
+	// cached once every dependent has been visited.
+	rank := make(map[string]int)
+	path := make(map[string]bool)
+	var effective func(id string, own int) int
+	effective = func(id string, own int) int {
+		if r, ok := rank[id]; ok {
+			return r
+		}
+		path[id] = true
+		defer delete(path, id)
+		r := own
+		for _, d := range dependents[id] {
+			if !path[d.ID] {
+				r = max(r, effective(d.ID, d.Priority.Rank()))
+			}
+		}
+		rank[id] = r
+		return r
+	}
+
+	initial := w.Initial()
+	var next []Task
+	for _, t := range tasks {
+		if t.Status == initial && len(blocked[t.ID]) == 0 {
+			effective(t.ID, t.Priority.Rank())
+			next = append(next, t)
+		}
+	}
+	SortTasks(next)
+	sort.SliceStable(next, func(i, j int) bool { return rank[next[i].ID] > rank[next[j].ID] })
+	return next
+}
+
+// NextTasks returns the tasks to work on next, see NextTasks
+func (ts *TaskStorage) NextTasks() []Task {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	return NextTasks(ts.tasks, ts.workflow)
+}
+
+// AddDependencies makes the task with the given ID depend on every task in
+// deps, so it is blocked until they are done. Dependencies it already has are
+// left as they are. It fails with a *ValidationError when a dependency does
+// not exist or would create a cycle.
+func (ts *TaskStorage) AddDependencies(id string, deps ...string) (*Task, error) {
+	return ts.changeDependencies(id, func(tx Store, t *Task) error {
+		for _, dep := range deps {
+			if slices.Contains(t.DependsOn, dep) {
+				continue
+			}
+			if err := validateDependency(tx, t.ID, dep); err != nil {
+				return err
+			}
+			t.DependsOn = append(t.DependsOn, dep)
+		}
+		return nil
+	})
+}
+
+// RemoveDependencies removes deps from the dependencies of the task with the
+// given ID. It fails with a *ValidationError when the task does not depend on
+// one of them.
+func (ts *TaskStorage) RemoveDependencies(id string, deps ...string) (*Task, error) {
+	return ts.changeDependencies(id, func(tx Store, t *Task) error {
+		for _, dep := range deps {
+			i := slices.Index(t.DependsOn, dep)
+			if i < 0 {
+				return &ValidationError{Field: "depends_on", Message: fmt.Sprintf("task %s does not depend on %s", t.ID, dep)}
+			}
+			t.DependsOn = slices.Delete(t.DependsOn, i, i+1)
+		}
+		if len(t.DependsOn) == 0 {
+			t.DependsOn = nil
+		}
+		return nil
+	})
+}
+
+func (ts *TaskStorage) changeDependencies(id string, change func(tx Store, t *Task) error) (*Task, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	var updated Task
+	err := ts.transaction(OpDeps, func(tx Store) error {
+		t, err := tx.Get(id)
+		if err != nil {
+			return err
+		}
+		t.DependsOn = slices.Clone(t.DependsOn)
+		if err := change(tx, &t); err != nil {
+			return err
+		}
+		t.UpdatedAt = time.Now()
+		updated = t
+		return tx.Put(t)
+	})
+	if errors.Is(err, ErrTaskNotFound) || errors.Is(err, ErrValidation) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save dependencies: %w", err)
+	}
+	return &updated, nil
+}
+
+// validateDependency checks that dep exists and that making id depend on it
+// would not create a cycle, i.e. that dep does not already depend on id
+func validateDependency(tx Store, id, dep string) error {
+	if dep == id {
+		return &ValidationError{Field: "depends_on", Message: fmt.Sprintf("task %s cannot depend on itself", id)}
+	}
+
+	seen := make(map[string]bool)
+	queue := []string{dep}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if seen[current] {
+			continue
+		}
+		seen[current] = true
+
+		t, err := tx.Get(current)
+		if errors.Is(err, ErrTaskNotFound) {
+			if current == dep {
+				return &ValidationError{Field: "depends_on", Message: fmt.Sprintf("dependency %s not found", dep)}
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if slices.Contains(t.DependsOn, id) {
+			return &ValidationError{Field: "depends_on", Message: fmt.Sprintf("task %s cannot depend on %s: it would create a cycle", id, dep)}
+		}
+		queue = append(queue, t.DependsOn...)
+	}
+	return nil
+}
+
+// dropDependencies removes the deleted tasks from the dependencies of the
+// remaining ones, so nothing stays blocked by a task that no longer exists
+func dropDependencies(tx Store, deleted map[string]bool, now time.Time) error {
+	tasks, err := tx.List()
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		deps := slices.DeleteFunc(slices.Clone(t.DependsOn), func(dep string) bool { return deleted[dep] })
+		if len(deps) == len(t.DependsOn) {
+			continue
+		}
+		if len(deps) == 0 {
+			deps = nil
+		}
+		t.DependsOn = deps
+		t.UpdatedAt = now
+		if err := tx.Put(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
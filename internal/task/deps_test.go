@@ -0,0 +1,129 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTaskStorage_Dependencies(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	design, _ := ts.AddTask("Design", "")
+	build, _ := ts.AddTask("Build", "")
+	ship, _ := ts.AddTask("Ship", "")
+
+	if _, err := ts.AddDependencies(build.ID, design.ID); err != nil {
+		t.Fatal(err)
+	}
+	updated, err := ts.AddDependencies(ship.ID, build.ID, build.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(updated.DependsOn, []string{build.ID}) {
+		t.Errorf("Expected a single dependency on %s, got %v", build.ID, updated.DependsOn)
+	}
+
+	for _, dep := range []string{ship.ID, design.ID} {
+		if _, err := ts.AddDependencies(design.ID, dep); !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected a validation error for %s depending on %s, got %v", design.ID, dep, err)
+		}
+	}
+	if _, err := ts.AddDependencies(design.ID, "missing"); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected a validation error for a missing dependency, got %v", err)
+	}
+
+	blocked := ts.BlockedBy()
+	if !reflect.DeepEqual(blocked, map[string][]string{build.ID: {design.ID}, ship.ID: {build.ID}}) {
+		t.Errorf("Unexpected blocked tasks %v", blocked)
+	}
+
+	done := StatusDone
+	if _, err := ts.UpdateTask(ctx, design.ID, TaskPatch{Status: &done}); err != nil {
+		t.Fatal(err)
+	}
+	if blocked := ts.BlockedBy(); len(blocked[build.ID]) != 0 || len(blocked[ship.ID]) != 1 {
+		t.Errorf("Expected only %s to stay blocked, got %v", ship.ID, blocked)
+	}
+
+	if _, err := ts.RemoveDependencies(ship.ID, design.ID); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected a validation error removing a missing dependency, got %v", err)
+	}
+	updated, err = ts.RemoveDependencies(ship.ID, build.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.DependsOn != nil {
+		t.Errorf("Expected no dependencies left, got %v", updated.DependsOn)
+	}
+
+	// Deleting a task releases the tasks waiting on it
+	if _, err := ts.AddDependencies(ship.ID, build.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.DeleteTask(ctx, build.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ts.GetTask(ship.ID); got.DependsOn != nil {
+		t.Errorf("Expected the dependency on the deleted task to be dropped, got %v", got.DependsOn)
+	}
+}
+
+func TestNextTasks(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	task := func(id string, p Priority, s Status, deps ...string) Task {
+		return Task{ID: id, Priority: p, Status: s, DependsOn: deps, CreatedAt: created}
+	}
+	tasks := []Task{
+		task("high", PriorityHigh, StatusTodo),
+		task("low", PriorityLow, StatusTodo),
+		task("urgent", PriorityUrgent, StatusTodo, "mid"),
+		task("mid", PriorityMedium, StatusTodo, "low"),
+		task("done", PriorityUrgent, StatusDone),
+		task("after-done", PriorityMedium, StatusTodo, "done"),
+		task("started", PriorityUrgent, StatusInProgress),
+	}
+
+	var ids []string
+	for _, t := range NextTasks(tasks, DefaultWorkflow) {
+		ids = append(ids, t.ID)
+	}
+	// low blocks mid which blocks urgent, so it comes first
+	expected := []string{"low", "high", "after-done"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected %v, got %v", expected, ids)
+	}
+}
+
+func TestNextTasks_Diamond(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	task := func(id string, p Priority, age int, deps ...string) Task {
+		return Task{ID: id, Priority: p, Status: StatusTodo, DependsOn: deps, CreatedAt: created.Add(time.Duration(age) * time.Hour)}
+	}
+	// d waits on b and c, which both wait on a; c also waits on e
+	tasks := []Task{
+		task("a", PriorityLow, 0),
+		task("f", PriorityHigh, 1),
+		task("e", PriorityLow, 2),
+		task("b", PriorityLow, 3, "a"),
+		task("c", PriorityLow, 4, "a", "e"),
+		task("d", PriorityUrgent, 5, "b", "c"),
+	}
+
+	var ids []string
+	for _, t := range NextTasks(tasks, DefaultWorkflow) {
+		ids = append(ids, t.ID)
+	}
+	// e unblocks the urgent d through c, so it comes before f
+	expected := []string{"a", "e", "f"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected %v, got %v", expected, ids)
+	}
+}
@@ -0,0 +1,135 @@
+package task
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateOnlyLayout is always accepted for due dates besides the configured format
+const dateOnlyLayout = "2006-01-02"
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// ParseDate parses a due date relative to now. It accepts dates in the given
+// layout, plain YYYY-MM-DD dates and phrases such as "today", "tomorrow",
+// "friday", "next friday", "in 3 days" or "in 2 weeks". A weekday alone is
+// its next occurrence after today, "next <weekday>" that day in the following
+// week, weeks starting on Monday: on a Thursday "friday" is tomorrow and "next
+// friday" eight days later. Dates without a time of day resolve to the end of
+// that day, so a task due today is not overdue until midnight.
+func ParseDate(s, layout string, now time.Time) (time.Time, error) {
+	input := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if input == "" {
+		return time.Time{}, dateError(s)
+	}
+
+	if layout != "" {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			if !hasClock(layout) {
+				return endOfDay(t), nil
+			}
+			return t, nil
+		}
+	}
+	if t, err := time.ParseInLocation(dateOnlyLayout, input, now.Location()); err == nil {
+		return endOfDay(t), nil
+	}
+
+	switch input {
+	case "today":
+		return endOfDay(now), nil
+	case "tomorrow":
+		return endOfDay(now.AddDate(0, 0, 1)), nil
+	case "yesterday":
+		return endOfDay(now.AddDate(0, 0, -1)), nil
+	}
+
+	words := strings.Fields(input)
+	switch {
+	case len(words) == 1:
+		if day, ok := weekdays[words[0]]; ok {
+			return endOfDay(nextWeekday(now, day)), nil
+		}
+	case len(words) == 2 && words[0] == "next":
+		if day, ok := weekdays[words[1]]; ok {
+			return endOfDay(weekdayNextWeek(now, day)), nil
+		}
+		switch words[1] {
+		case "week":
+			return endOfDay(now.AddDate(0, 0, 7)), nil
+		case "month":
+			return endOfDay(now.AddDate(0, 1, 0)), nil
+		}
+	case len(words) == 3 && words[0] == "in":
+		n, err := strconv.Atoi(words[1])
+		if err != nil || n < 0 {
+			break
+		}
+		switch strings.TrimSuffix(words[2], "s") {
+		case "minute":
+			return now.Add(time.Duration(n) * time.Minute), nil
+		case "hour":
+			return now.Add(time.Duration(n) * time.Hour), nil
+		case "day":
+			return endOfDay(now.AddDate(0, 0, n)), nil
+		case "week":
+			return endOfDay(now.AddDate(0, 0, 7*n)), nil
+		case "month":
+			return endOfDay(now.AddDate(0, n, 0)), nil
+		}
+	}
+
+	return time.Time{}, dateError(s)
+}
+
+func dateError(s string) error {
+	return &ValidationError{
+		Field:   "due",
+		Message: fmt.Sprintf("invalid date: %q. Use the configured date format, YYYY-MM-DD or a phrase like \"tomorrow\", \"next friday\" or \"in 3 days\"", s),
+	}
+}
+
+// nextWeekday returns the first day strictly after now falling on day
+func nextWeekday(now time.Time, day time.Weekday) time.Time {
+	days := (int(day) - int(now.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return now.AddDate(0, 0, days)
+}
+
+// weekdayNextWeek returns the day falling on day in the week after the one of
+// now, weeks starting on Monday as in reports
+func weekdayNextWeek(now time.Time, day time.Weekday) time.Time {
+	monday := now.AddDate(0, 0, 7-(int(now.Weekday())+6)%7)
+	return monday.AddDate(0, 0, (int(day)+6)%7)
+}
+
+// hasClock reports whether layout includes a time of day, i.e. whether two
+// instants of the same day format differently with it
+func hasClock(layout string) bool {
+	midnight := time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC)
+	afternoon := time.Date(2006, 1, 2, 15, 4, 5, 999999999, time.UTC)
+	return midnight.Format(layout) != afternoon.Format(layout)
+}
+
+func endOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 23, 59, 59, 0, t.Location())
+}
+
+// IsOverdue reports whether the task has a due date before now and is not
+// done in workflow w
+func (t Task) IsOverdue(now time.Time, w *Workflow) bool {
+	return t.DueAt != nil && !w.IsDone(t.Status) && t.DueAt.Before(now)
+}
@@ -0,0 +1,90 @@
+package task
+
+import (
+	"errors"
+	"os"
+	"os/user"
+	"time"
+)
+
+// FieldCreated is the pseudo field of the history event recorded when a task is created
+const FieldCreated = "created"
+
+// Change is a field level event in the history of a task
+type Change struct {
+	FieldChange
+	At    time.Time `json:"at"`
+	Actor string    `json:"actor,omitempty"`
+}
+
+// historyStore wraps a transaction and appends a Change to a task's history
+// for every field that differs from the stored version. History is always
+// carried forward from the stored task, so callers can't rewrite it.
+type historyStore struct {
+	Store
+	actor string
+	now   time.Time
+}
+
+func (h *historyStore) Put(t Task) error {
+	prev, err := h.Store.Get(t.ID)
+	switch {
+	case err == nil:
+		t.History = append(append([]Change{}, prev.History...), h.changes(diffTasks(prev, t))...)
+	case errors.Is(err, ErrTaskNotFound):
+		if len(t.History) == 0 {
+			t.History = []Change{{
+				FieldChange: FieldChange{Field: FieldCreated, New: t.Title},
+				At:          t.CreatedAt,
+				Actor:       h.actor,
+			}}
+		}
+	default:
+		return err
+	}
+
+	return h.Store.Put(t)
+}
+
+func (h *historyStore) Transaction(fn func(tx Store) error) error {
+	return fn(h)
+}
+
+func (h *historyStore) changes(fields []FieldChange) []Change {
+	changes := make([]Change, 0, len(fields))
+	for _, f := range fields {
+		changes = append(changes, Change{FieldChange: f, At: h.now, Actor: h.actor})
+	}
+	return changes
+}
+
+// actor returns the name recorded in history events: the configured actor,
+// falling back to the operating system user.
+func (ts *TaskStorage) actor() string {
+	if ts.cfg.Task.Actor != "" {
+		return ts.cfg.Task.Actor
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// TaskHistory returns the change events of the task, oldest first. Tasks
+// created before history was recorded get a synthesized creation event.
+func (ts *TaskStorage) TaskHistory(id string) ([]Change, error) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	_, task, err := ts.findTaskById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	events := append([]Change{}, task.History...)
+	if len(events) == 0 || events[0].Field != FieldCreated {
+		created := Change{FieldChange: FieldChange{Field: FieldCreated, New: task.Title}, At: task.CreatedAt}
+		events = append([]Change{created}, events...)
+	}
+	return events, nil
+}
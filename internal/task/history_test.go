@@ -0,0 +1,63 @@
+package task
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTaskStorage_TaskHistory(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	cfg.Task.Actor = "alice"
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	added, err := ts.AddTask("Write docs", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.UpdateTask(ctx, added.ID, TaskPatch{Status: ptr(Status("ip"))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.UpdateTask(ctx, added.ID, TaskPatch{Status: ptr(StatusDone), Title: ptr("Write the docs")}); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := ts.TaskHistory(added.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []FieldChange{
+		{Field: FieldCreated, New: "Write docs"},
+		{Field: "status", Old: "TODO", New: "IN_PROGRESS"},
+		{Field: "title", Old: "Write docs", New: "Write the docs"},
+		{Field: "status", Old: "IN_PROGRESS", New: "DONE"},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %+v", len(expected), events)
+	}
+	for i, want := range expected {
+		if events[i].FieldChange != want {
+			t.Errorf("Event %d: expected %+v, got %+v", i, want, events[i].FieldChange)
+		}
+		if events[i].Actor != "alice" || events[i].At.IsZero() {
+			t.Errorf("Event %d: expected actor and timestamp, got %q at %v", i, events[i].Actor, events[i].At)
+		}
+	}
+
+	// Undo keeps the timeline and records the revert as new events
+	if _, err := ts.Undo(false); err != nil {
+		t.Fatal(err)
+	}
+	events, _ = ts.TaskHistory(added.ID)
+	if len(events) != 6 || events[5].Field != "status" || events[5].New != "IN_PROGRESS" {
+		t.Errorf("Expected undo to append revert events, got %+v", events)
+	}
+
+	if _, err := ts.TaskHistory("missing"); err == nil {
+		t.Error("Expected error for unknown task")
+	}
+}
@@ -0,0 +1,302 @@
+package task
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/config"
+	"github.com/google/uuid"
+)
+
+// OpMigrateIDs is the journal kind of ID migrations
+const OpMigrateIDs = "migrate-ids"
+
+// maxIDAttempts bounds the retries when a generated ID is already taken
+const maxIDAttempts = 10
+
+var (
+	// ErrAmbiguousID is matched by every AmbiguousIDError
+	ErrAmbiguousID = errors.New("ambiguous task ID")
+	// ErrIDCollision is returned when no free ID could be generated
+	ErrIDCollision = errors.New("could not generate a unique task ID")
+)
+
+// crockford is the alphabet of ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// AmbiguousIDError reports an ID prefix matching several tasks
+type AmbiguousIDError struct {
+	Prefix     string
+	Candidates []Task
+}
+
+func (e *AmbiguousIDError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "task ID prefix %q is ambiguous, it matches %d tasks:", e.Prefix, len(e.Candidates))
+	for _, t := range e.Candidates {
+		fmt.Fprintf(&b, "\n  %s  %s", t.ID, t.Title)
+	}
+	return b.String()
+}
+
+func (e *AmbiguousIDError) Unwrap() error {
+	return ErrAmbiguousID
+}
+
+// ResolveID returns the full ID of the task whose ID or former ID is id or,
+// git-style, starts with id. It fails with ErrTaskNotFound when nothing matches and an
+// *AmbiguousIDError when the prefix matches several tasks.
+func (ts *TaskStorage) ResolveID(id string) (string, error) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	if id == "" {
+		return "", ErrInvalidTaskID
+	}
+
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	var candidates []Task
+	for _, task := range ts.tasks {
+		if task.ID == id {
+			return task.ID, nil
+		}
+		if strings.HasPrefix(task.ID, id) {
+			candidates = append(candidates, task)
+			continue
+		}
+		// IDs replaced by MigrateIDs keep resolving to the task
+		for _, alias := range task.Aliases {
+			if alias == id {
+				return task.ID, nil
+			}
+			if strings.HasPrefix(alias, id) {
+				candidates = append(candidates, task)
+				break
+			}
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", ErrTaskNotFound, id)
+	case 1:
+		return candidates[0].ID, nil
+	}
+	return "", &AmbiguousIDError{Prefix: id, Candidates: candidates}
+}
+
+// newID returns an ID following strategy. Sequential IDs use next.
+func newID(strategy string, next int) (string, error) {
+	switch strategy {
+	case config.IDSequential, "":
+		return strconv.Itoa(next), nil
+	case config.IDULID:
+		return newULID(time.Now())
+	case config.IDUUID:
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return "", err
+		}
+		return id.String(), nil
+	case config.IDShort:
+		return generateTaskID()
+	}
+	return "", fmt.Errorf("unknown task ID strategy %q, use one of: %s, %s, %s, %s",
+		strategy, config.IDSequential, config.IDULID, config.IDUUID, config.IDShort)
+}
+
+// newULID returns a ULID: a 48 bit millisecond timestamp followed by 80
+// random bits, encoded as 26 Crockford base32 characters. ULIDs sort by
+// creation time. They are lower-cased like every other task ID.
+func newULID(now time.Time) (string, error) {
+	var data [16]byte
+	ms := uint64(now.UnixMilli())
+	for i := 0; i < 6; i++ {
+		data[i] = byte(ms >> (40 - 8*i))
+	}
+	if _, err := rand.Read(data[6:]); err != nil {
+		return "", err
+	}
+
+	// 128 bits in 26 characters of 5 bits; the first character holds 3 bits
+	var b strings.Builder
+	for i := 0; i < 26; i++ {
+		bit := 128 - 5*(26-i) // Offset of the character's lowest bit from the left, may be negative
+		var v byte
+		for j := 0; j < 5; j++ {
+			pos := bit + j
+			if pos < 0 {
+				continue
+			}
+			if data[pos/8]&(0x80>>(pos%8)) != 0 {
+				v |= 0x10 >> j
+			}
+		}
+		b.WriteByte(crockford[v])
+	}
+	return strings.ToLower(b.String()), nil
+}
+
+// isLegacyID reports whether id has the 8 hex character format generated
+// before ID strategies existed
+func isLegacyID(id string) bool {
+	if len(id) != 8 {
+		return false
+	}
+	for _, r := range id {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// nextSequence returns the sequential ID following the highest one in tasks.
+// Legacy 8 character IDs made only of digits are not counted, so they don't
+// make the sequence jump.
+func nextSequence(tasks []Task) int {
+	next := 1
+	for _, t := range tasks {
+		if isLegacyID(t.ID) {
+			continue
+		}
+		if n, err := strconv.Atoi(t.ID); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	return next
+}
+
+// assignID gives t an ID following strategy that no task in tx uses,
+// neither as ID nor as alias
+func assignID(tx Store, t *Task, strategy string) error {
+	tasks, err := tx.List()
+	if err != nil {
+		return err
+	}
+	taken := make(map[string]bool, len(tasks))
+	for _, existing := range tasks {
+		taken[existing.ID] = true
+		for _, alias := range existing.Aliases {
+			taken[alias] = true
+		}
+	}
+
+	// The recorded sequence covers deleted tasks, so their IDs are never
+	// issued again: undo, history and occurrence links keep pointing to them
+	issued, err := tx.Sequence()
+	if err != nil {
+		return err
+	}
+	next := max(nextSequence(tasks), issued+1)
+	for attempt := 0; attempt < maxIDAttempts; attempt++ {
+		id, err := newID(strategy, next+attempt)
+		if err != nil {
+			return err
+		}
+		if taken[id] {
+			continue
+		}
+		t.ID = id
+		if strategy == config.IDSequential || strategy == "" {
+			return tx.SetSequence(next + attempt)
+		}
+		return nil
+	}
+	return ErrIDCollision
+}
+
+// MigrateIDs gives every task with a legacy 8 character ID a new ID following
+// the configured strategy, oldest task first. The old ID is kept as an alias,
+// so it still resolves, and references from subtasks and dependent tasks
+// follow it to its new ID. It returns the new ID of every migrated task keyed by
+// its old ID. The short strategy is rejected: it generates IDs in the
+// legacy format, which a later run would migrate again.
+func (ts *TaskStorage) MigrateIDs() (map[string]string, error) {
+	if ts.cfg.Task.IDStrategy == config.IDShort {
+		return nil, &ValidationError{Field: "idStrategy", Message: fmt.Sprintf(
+			"cannot migrate IDs to the %s strategy, it generates IDs in the old format. Use one of: %s, %s, %s",
+			config.IDShort, config.IDSequential, config.IDULID, config.IDUUID)}
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	migrated := make(map[string]string)
+	err := ts.transaction(OpMigrateIDs, func(tx Store) error {
+		tasks, err := tx.List()
+		if err != nil {
+			return err
+		}
+		sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].CreatedAt.Before(tasks[j].CreatedAt) })
+
+		now := time.Now()
+		for _, task := range tasks {
+			if !isLegacyID(task.ID) {
+				continue
+			}
+			old := task.ID
+			if err := assignID(tx, &task, ts.cfg.Task.IDStrategy); err != nil {
+				return err
+			}
+			if task.ID == old {
+				continue
+			}
+			task.Aliases = append(task.Aliases, old)
+			task.History = append(task.History, Change{
+				FieldChange: FieldChange{Field: "id", Old: old, New: task.ID},
+				At:          now,
+				Actor:       ts.actor(),
+			})
+			task.UpdatedAt = now
+			if err := tx.Delete(old); err != nil {
+				return err
+			}
+			if err := tx.Put(task); err != nil {
+				return err
+			}
+			migrated[old] = task.ID
+		}
+		if len(migrated) == 0 {
+			return nil
+		}
+
+		// Point subtasks, dependent tasks and occurrences at the new IDs
+		tasks, err = tx.List()
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			changed := false
+			for _, link := range []*string{&task.ParentID, &task.PreviousID, &task.NextID} {
+				if id, ok := migrated[*link]; ok {
+					*link = id
+					changed = true
+				}
+			}
+			task.DependsOn = slices.Clone(task.DependsOn)
+			for i, dep := range task.DependsOn {
+				if id, ok := migrated[dep]; ok {
+					task.DependsOn[i] = id
+					changed = true
+				}
+			}
+			if changed {
+				if err := tx.Put(task); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error migrating task IDs: %w", err)
+	}
+	return migrated, nil
+}
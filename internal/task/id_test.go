@@ -0,0 +1,242 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/config"
+)
+
+func TestTaskStorage_ResolveID(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	store, err := OpenStore(cfg.Storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for _, id := range []string{"1a2b3c4d", "1a2f0000", "9f8e7d6c"} {
+		if err := store.Put(Task{ID: id, Title: "Task " + id, Status: StatusTodo, CreatedAt: now, UpdatedAt: now}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ts, err := NewTaskStorageWithStore(store, &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	scenarios := []struct {
+		name        string
+		input       string
+		expectedID  string
+		expectedErr error
+	}{
+		{"Full ID", "1a2b3c4d", "1a2b3c4d", nil},
+		{"Unique prefix", "9f", "9f8e7d6c", nil},
+		{"Upper-case prefix", "1A2B", "1a2b3c4d", nil},
+		{"Ambiguous prefix", "1a2", "", ErrAmbiguousID},
+		{"Unknown prefix", "ff", "", ErrTaskNotFound},
+		{"Empty ID", "", "", ErrInvalidTaskID},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			id, err := ts.ResolveID(scenario.input)
+
+			if !errors.Is(err, scenario.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", scenario.expectedErr, err)
+			}
+			if id != scenario.expectedID {
+				t.Errorf("Expected ID %q, got %q", scenario.expectedID, id)
+			}
+		})
+	}
+
+	_, err = ts.ResolveID("1a2")
+	var ambiguous *AmbiguousIDError
+	if !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 2 {
+		t.Errorf("Expected the two candidates to be listed, got %v", err)
+	}
+}
+
+func TestNewULID(t *testing.T) {
+	first, err := newULID(time.UnixMilli(1_700_000_000_000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := newULID(time.UnixMilli(1_700_000_000_001))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(first) != 26 {
+		t.Errorf("Expected 26 characters, got %q", first)
+	}
+	if first >= second {
+		t.Errorf("Expected ULIDs to sort by time, got %q >= %q", first, second)
+	}
+	// The timestamp is the first 10 characters
+	if first[:10] != "01hf7yat00" {
+		t.Errorf("Unexpected timestamp part %q", first[:10])
+	}
+}
+
+func TestTaskStorage_AddTaskIDStrategies(t *testing.T) {
+	scenarios := []struct {
+		strategy string
+		length   int
+	}{
+		{config.IDULID, 26},
+		{config.IDUUID, 36},
+		{config.IDShort, 8},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.strategy, func(t *testing.T) {
+			cfg := testConfig(t, "tasks.json")
+			cfg.Task.IDStrategy = scenario.strategy
+			ts, err := NewTaskStorageWithConfig(&cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ts.Close()
+
+			task, err := ts.AddTask("Task", "")
+			if err != nil {
+				t.Fatalf("AddTask failed: %v", err)
+			}
+			if len(task.ID) != scenario.length {
+				t.Errorf("Expected a %d character ID, got %q", scenario.length, task.ID)
+			}
+		})
+	}
+
+	cfg := testConfig(t, "tasks.json")
+	cfg.Task.IDStrategy = "words"
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	if _, err := ts.AddTask("Task", ""); err == nil {
+		t.Error("Expected an error for an unknown strategy")
+	}
+}
+
+func TestTaskStorage_SequentialIDs(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	store, err := OpenStore(cfg.Storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A legacy ID made only of digits must not make the sequence jump
+	now := time.Now()
+	if err := store.Put(Task{ID: "12345678", Title: "Legacy", Status: StatusTodo, CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	ts, err := NewTaskStorageWithStore(store, &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		task, err := ts.AddTask("Task", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, task.ID)
+	}
+	if ids[0] != "1" || ids[1] != "2" || ids[2] != "3" {
+		t.Errorf("Expected IDs 1, 2, 3, got %v", ids)
+	}
+
+	// IDs of deleted tasks other than the highest are never reused
+	if err := ts.DeleteTask(context.Background(), "2"); err != nil {
+		t.Fatal(err)
+	}
+	task, err := ts.AddTask("Task", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.ID != "4" {
+		t.Errorf("Expected ID 4, got %q", task.ID)
+	}
+
+	// Neither is the highest one, even by another storage on the same file
+	if err := ts.DeleteTask(context.Background(), "4"); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	task, err = reopened.AddTask("Task", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.ID != "5" {
+		t.Errorf("Expected ID 5 after deleting task 4, got %q", task.ID)
+	}
+}
+
+func TestTaskStorage_MigrateIDs(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	store, err := OpenStore(cfg.Storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for i, id := range []string{"9f8e7d6c", "1a2b3c4d"} {
+		created := now.Add(time.Duration(i) * time.Minute)
+		var parent string
+		if id == "1a2b3c4d" {
+			parent = "9f8e7d6c"
+		}
+		if err := store.Put(Task{ID: id, Title: "Task " + id, Status: StatusTodo, ParentID: parent, CreatedAt: created, UpdatedAt: created}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ts, err := NewTaskStorageWithStore(store, &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	migrated, err := ts.MigrateIDs()
+	if err != nil {
+		t.Fatalf("MigrateIDs failed: %v", err)
+	}
+	if migrated["9f8e7d6c"] != "1" || migrated["1a2b3c4d"] != "2" {
+		t.Errorf("Expected IDs assigned oldest first, got %v", migrated)
+	}
+
+	for old, want := range map[string]string{"9f8e7d6c": "1", "1a2b": "2"} {
+		id, err := ts.ResolveID(old)
+		if err != nil || id != want {
+			t.Errorf("Expected %q to resolve to %q, got %q (%v)", old, want, id, err)
+		}
+	}
+
+	if child, _ := ts.GetTask("2"); child.ParentID != "1" {
+		t.Errorf("Expected the subtask to follow its parent to ID 1, got %q", child.ParentID)
+	}
+
+	events, err := ts.TaskHistory("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := events[len(events)-1]; last.Field != "id" || last.Old != "9f8e7d6c" {
+		t.Errorf("Expected the ID change in the history, got %+v", last)
+	}
+
+	// Short IDs look like legacy ones, every run would migrate them again
+	ts.cfg.Task.IDStrategy = config.IDShort
+	if _, err := ts.MigrateIDs(); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected a validation error for the short strategy, got %v", err)
+	}
+}
@@ -0,0 +1,488 @@
+package task
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Journal operation kinds. Mutations record their own kind; undo and redo
+// entries point at the operation they revert or re-apply.
+const (
+	OpAdd     = "add"
+	OpUpdate  = "update"
+	OpDelete  = "delete"
+	OpClear   = "clear"
+	OpImport  = "import"
+	OpRestore = "restore"
+	OpUndo    = "undo"
+	OpRedo    = "redo"
+)
+
+var (
+	ErrNothingToUndo   = errors.New("nothing to undo")
+	ErrNothingToRedo   = errors.New("nothing to redo")
+	ErrJournalConflict = errors.New("tasks changed since the operation was recorded")
+)
+
+// FieldChange is the old and new value of a single task field
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// TaskChange is the state of one task before and after an operation.
+// A nil Before means the task was created, a nil After that it was deleted.
+type TaskChange struct {
+	ID     string        `json:"id"`
+	Before *Task         `json:"before,omitempty"`
+	After  *Task         `json:"after,omitempty"`
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// Operation is one entry of the journal
+type Operation struct {
+	Seq     int64        `json:"seq"`
+	Kind    string       `json:"kind"`
+	At      time.Time    `json:"at"`
+	Changes []TaskChange `json:"changes,omitempty"`
+	Target  int64        `json:"target,omitempty"` // Operation reverted or re-applied by undo/redo
+}
+
+// HistoryEntry is an operation as shown by `undo --list`
+type HistoryEntry struct {
+	Operation
+	Undone bool // The operation is currently reverted and can be redone
+}
+
+// Summary describes the operation in one line
+func (op Operation) Summary() string {
+	var parts []string
+	for _, c := range op.Changes {
+		switch {
+		case c.Before == nil && c.After != nil:
+			parts = append(parts, fmt.Sprintf("added %s %q", c.ID, c.After.Title))
+		case c.Before != nil && c.After == nil:
+			parts = append(parts, fmt.Sprintf("deleted %s %q", c.ID, c.Before.Title))
+		default:
+			var fields []string
+			for _, f := range c.Fields {
+				fields = append(fields, fmt.Sprintf("%s: %q -> %q", f.Field, f.Old, f.New))
+			}
+			parts = append(parts, fmt.Sprintf("updated %s (%s)", c.ID, strings.Join(fields, ", ")))
+		}
+	}
+
+	if len(parts) > 3 {
+		return fmt.Sprintf("%s and %d more", strings.Join(parts[:3], "; "), len(parts)-3)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Journal is the append-only log of operations kept next to the tasks file
+type Journal struct {
+	path string
+}
+
+// NewJournal returns the journal stored at path
+func NewJournal(path string) *Journal {
+	return &Journal{path: path}
+}
+
+// Load reads all operations, oldest first. A missing journal is empty.
+func (j *Journal) Load() ([]Operation, error) {
+	f, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening journal: %w", err)
+	}
+	defer f.Close()
+
+	var ops []Operation
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var op Operation
+		if err := json.Unmarshal(line, &op); err != nil {
+			// A torn last line from a crash must not make the journal unusable
+			continue
+		}
+		ops = append(ops, op)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading journal: %w", err)
+	}
+
+	return ops, nil
+}
+
+// Append adds op to the journal, assigning it the sequence number following
+// the last entry. Callers must hold the lock of the tasks store, so that
+// concurrent processes don't give their entries the same number.
+func (j *Journal) Append(op Operation) (Operation, error) {
+	op, _, err := j.append(op)
+	return op, err
+}
+
+// append is Append, also returning the offset the entry was written at
+func (j *Journal) append(op Operation) (Operation, int64, error) {
+	seq, err := j.lastSeq()
+	if err != nil {
+		return op, 0, err
+	}
+	op.Seq = seq + 1
+	if op.At.IsZero() {
+		op.At = time.Now()
+	}
+
+	data, err := json.Marshal(op)
+	if err != nil {
+		return op, 0, fmt.Errorf("error serializing journal entry: %w", err)
+	}
+
+	f, err := os.OpenFile(j.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return op, 0, fmt.Errorf("error opening journal: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return op, 0, fmt.Errorf("error opening journal: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return op, 0, fmt.Errorf("error writing journal: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return op, 0, fmt.Errorf("error syncing journal: %w", err)
+	}
+	return op, info.Size(), f.Close()
+}
+
+// discard removes the entry appended at offset, for operations whose
+// transaction failed after being journaled. Entries appended since by other
+// processes are left alone.
+func (j *Journal) discard(op Operation, offset int64) error {
+	seq, err := j.lastSeq()
+	if err != nil || seq != op.Seq {
+		return err
+	}
+	return os.Truncate(j.path, offset)
+}
+
+// lastSeq returns the sequence number of the last readable entry, 0 for an
+// empty journal. The file is read backwards from its end, so appending
+// doesn't get slower as the journal grows.
+func (j *Journal) lastSeq() (int64, error) {
+	f, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error opening journal: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("error reading journal: %w", err)
+	}
+
+	const chunk = 64 * 1024
+	var tail []byte
+	for pos := info.Size(); pos > 0; {
+		n := min(chunk, pos)
+		pos -= n
+		buf := make([]byte, n, n+int64(len(tail)))
+		if _, err := f.ReadAt(buf, pos); err != nil {
+			return 0, fmt.Errorf("error reading journal: %w", err)
+		}
+		tail = append(buf, tail...)
+
+		for {
+			lines := bytes.TrimRight(tail, "\n")
+			i := bytes.LastIndexByte(lines, '\n')
+			if i < 0 && pos > 0 {
+				break // The last line starts before the part read so far
+			}
+			var entry struct {
+				Seq int64 `json:"seq"`
+			}
+			if err := json.Unmarshal(lines[i+1:], &entry); err == nil {
+				return entry.Seq, nil
+			}
+			// A torn last line from a crash is skipped, as by Load
+			if i < 0 {
+				return 0, nil
+			}
+			tail = lines[:i+1]
+		}
+	}
+	return 0, nil
+}
+
+// stacks replays the journal into the operations that can be undone and the
+// ones that can be redone, most recent last.
+func stacks(ops []Operation) (done, undone []Operation) {
+	bySeq := make(map[int64]Operation, len(ops))
+	for _, op := range ops {
+		bySeq[op.Seq] = op
+	}
+
+	for _, op := range ops {
+		switch op.Kind {
+		case OpUndo:
+			if n := len(done); n > 0 && done[n-1].Seq == op.Target {
+				undone = append(undone, done[n-1])
+				done = done[:n-1]
+			}
+		case OpRedo:
+			if n := len(undone); n > 0 && undone[n-1].Seq == op.Target {
+				done = append(done, undone[n-1])
+				undone = undone[:n-1]
+			}
+		default:
+			done = append(done, bySeq[op.Seq])
+			// A new change invalidates whatever could be redone
+			undone = nil
+		}
+	}
+
+	return done, undone
+}
+
+// recordingStore wraps a transaction and remembers the state of every task
+// it touches before and after the change.
+type recordingStore struct {
+	Store
+	changes []TaskChange
+	index   map[string]int
+}
+
+func newRecordingStore(tx Store) *recordingStore {
+	return &recordingStore{Store: tx, index: map[string]int{}}
+}
+
+func (r *recordingStore) touch(id string) (int, error) {
+	if i, ok := r.index[id]; ok {
+		return i, nil
+	}
+
+	change := TaskChange{ID: id}
+	before, err := r.Store.Get(id)
+	if err == nil {
+		change.Before = &before
+	} else if !errors.Is(err, ErrTaskNotFound) {
+		return 0, err
+	}
+
+	r.index[id] = len(r.changes)
+	r.changes = append(r.changes, change)
+	return len(r.changes) - 1, nil
+}
+
+func (r *recordingStore) Put(t Task) error {
+	i, err := r.touch(t.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.Store.Put(t); err != nil {
+		return err
+	}
+	r.changes[i].After = &t
+	return nil
+}
+
+func (r *recordingStore) Delete(id string) error {
+	i, err := r.touch(id)
+	if err != nil {
+		return err
+	}
+	if err := r.Store.Delete(id); err != nil {
+		return err
+	}
+	r.changes[i].After = nil
+	return nil
+}
+
+func (r *recordingStore) Transaction(fn func(tx Store) error) error {
+	return fn(r)
+}
+
+// result returns the recorded changes, leaving out tasks that ended up as they started
+func (r *recordingStore) result() []TaskChange {
+	var changes []TaskChange
+	for _, c := range r.changes {
+		if c.Before == nil && c.After == nil {
+			continue
+		}
+		if c.Before != nil && c.After != nil {
+			c.Fields = diffTasks(*c.Before, *c.After)
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// diffTasks lists the user visible fields that differ between two versions of a task
+func diffTasks(before, after Task) []FieldChange {
+	var fields []FieldChange
+	add := func(field, old, new string) {
+		if old != new {
+			fields = append(fields, FieldChange{Field: field, Old: old, New: new})
+		}
+	}
+
+	add("title", before.Title, after.Title)
+	add("description", before.Description, after.Description)
+	add("status", string(before.Status), string(after.Status))
+	add("priority", string(before.Priority), string(after.Priority))
+	add("due", formatDue(before.DueAt), formatDue(after.DueAt))
+	add("reminder", formatEstimate(before.Reminder), formatEstimate(after.Reminder))
+	add("tags", strings.Join(before.Tags, ","), strings.Join(after.Tags, ","))
+	add("parent", before.ParentID, after.ParentID)
+	add("depends_on", strings.Join(before.DependsOn, ","), strings.Join(after.DependsOn, ","))
+	add("estimate", formatEstimate(before.Estimate), formatEstimate(after.Estimate))
+	add("time", formatWork(before), formatWork(after))
+	add("recurrence", formatRecurrence(before.Recurrence), formatRecurrence(after.Recurrence))
+	add("next", before.NextID, after.NextID)
+
+	return fields
+}
+
+func formatDue(due *time.Time) string {
+	if due == nil {
+		return ""
+	}
+	return due.Format(time.RFC3339)
+}
+
+// matches reports whether the stored task (nil when missing) is the state
+// expected by the journal (nil when it should not exist).
+func matches(current, expected *Task) bool {
+	if current == nil || expected == nil {
+		return current == nil && expected == nil
+	}
+	return current.UpdatedAt.Equal(expected.UpdatedAt)
+}
+
+// replay moves every task of changes to the given side (before for undo,
+// after for redo). Unless force is set it first checks that the tasks are
+// still in the state the other side describes.
+func replay(tx Store, changes []TaskChange, toBefore, force bool) error {
+	for i := len(changes) - 1; i >= 0; i-- {
+		c := changes[i]
+		from, to := c.After, c.Before
+		if !toBefore {
+			from, to = c.Before, c.After
+		}
+
+		var current *Task
+		t, err := tx.Get(c.ID)
+		if err == nil {
+			current = &t
+		} else if !errors.Is(err, ErrTaskNotFound) {
+			return err
+		}
+
+		if !force && !matches(current, from) {
+			return fmt.Errorf("%w: task %s (use --force to override)", ErrJournalConflict, c.ID)
+		}
+
+		switch {
+		case to != nil:
+			err = tx.Put(*to)
+		case current != nil:
+			err = tx.Delete(c.ID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Undo reverts the most recent operation that hasn't been undone yet
+func (ts *TaskStorage) Undo(force bool) (Operation, error) {
+	return ts.step(OpUndo, force)
+}
+
+// Redo re-applies the most recently undone operation
+func (ts *TaskStorage) Redo(force bool) (Operation, error) {
+	return ts.step(OpRedo, force)
+}
+
+func (ts *TaskStorage) step(kind string, force bool) (Operation, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	// The journal is read and written while the store is locked, so that a
+	// concurrent process cannot undo the same operation
+	var target Operation
+	err := ts.transaction("", func(tx Store) error {
+		ops, err := ts.journal.Load()
+		if err != nil {
+			return err
+		}
+
+		done, undone := stacks(ops)
+		switch {
+		case kind == OpUndo && len(done) > 0:
+			target = done[len(done)-1]
+		case kind == OpRedo && len(undone) > 0:
+			target = undone[len(undone)-1]
+		case kind == OpUndo:
+			return ErrNothingToUndo
+		default:
+			return ErrNothingToRedo
+		}
+
+		if err := replay(tx, target.Changes, kind == OpUndo, force); err != nil {
+			return err
+		}
+		return ts.journalize(Operation{Kind: kind, Target: target.Seq})
+	})
+	if err != nil {
+		return Operation{}, err
+	}
+	return target, nil
+}
+
+// History returns the last limit undoable or redoable operations, newest first.
+// A limit of zero or less returns all of them.
+func (ts *TaskStorage) History(limit int) ([]HistoryEntry, error) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	ops, err := ts.journal.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	done, undone := stacks(ops)
+	var entries []HistoryEntry
+	for _, op := range undone {
+		entries = append(entries, HistoryEntry{Operation: op, Undone: true})
+	}
+	for i := len(done) - 1; i >= 0; i-- {
+		entries = append(entries, HistoryEntry{Operation: done[i]})
+	}
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
@@ -0,0 +1,183 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJournal_Append(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json.journal")
+	j := NewJournal(path)
+
+	// Entries larger than the chunks read from the end of the file
+	big := Task{ID: "1", Title: "Task", Description: strings.Repeat("x", 100*1024)}
+	for i := 1; i <= 3; i++ {
+		op, err := j.Append(Operation{Kind: OpAdd, Changes: []TaskChange{{ID: "1", After: &big}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op.Seq != int64(i) {
+			t.Errorf("Expected seq %d, got %d", i, op.Seq)
+		}
+	}
+
+	// A torn last line from a crash is skipped
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"seq":4,"kind":"ad` + "\n")
+	f.Close()
+	if op, err := j.Append(Operation{Kind: OpDelete}); err != nil || op.Seq != 4 {
+		t.Errorf("Expected seq 4 after a torn line, got %d (%v)", op.Seq, err)
+	}
+}
+
+func TestTaskStorage_UndoRedo(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	first, err := ts.AddTask("First", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := ts.AddTask("Second", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.UpdateTask(ctx, first.ID, TaskPatch{Title: ptr("First (renamed)"), Status: ptr(StatusDone)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.DeleteTask(ctx, second.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Undo the delete, then the update
+	op, err := ts.Undo(false)
+	if err != nil || op.Kind != OpDelete {
+		t.Fatalf("Expected to undo the delete, got %v (%v)", op.Kind, err)
+	}
+	if _, err := ts.GetTask(second.ID); err != nil {
+		t.Errorf("Expected deleted task to be restored: %v", err)
+	}
+
+	op, err = ts.Undo(false)
+	if err != nil || op.Kind != OpUpdate {
+		t.Fatalf("Expected to undo the update, got %v (%v)", op.Kind, err)
+	}
+	restored, _ := ts.GetTask(first.ID)
+	if restored.Title != "First" || restored.Status != StatusTodo {
+		t.Errorf("Expected original title and status, got %q %s", restored.Title, restored.Status)
+	}
+	if len(op.Changes) != 1 || len(op.Changes[0].Fields) != 2 {
+		t.Errorf("Expected the update to record title and status changes, got %+v", op.Changes)
+	}
+
+	history, err := ts.History(0)
+	if err != nil || len(history) != 4 || !history[0].Undone || !history[1].Undone || history[2].Undone {
+		t.Fatalf("Unexpected history: %+v (%v)", history, err)
+	}
+
+	// Redo the update
+	op, err = ts.Redo(false)
+	if err != nil || op.Kind != OpUpdate {
+		t.Fatalf("Expected to redo the update, got %v (%v)", op.Kind, err)
+	}
+	redone, _ := ts.GetTask(first.ID)
+	if redone.Title != "First (renamed)" {
+		t.Errorf("Expected redone title, got %q", redone.Title)
+	}
+
+	// A new change discards the remaining redo
+	if _, err := ts.AddTask("Third", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.Redo(false); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("Expected ErrNothingToRedo after a new change, got %v", err)
+	}
+}
+
+func TestTaskStorage_UndoClear(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ts.Undo(false); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Expected ErrNothingToUndo on an empty journal, got %v", err)
+	}
+
+	for _, title := range []string{"One", "Two", "Three"} {
+		if _, err := ts.AddTask(title, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = ts.transaction(OpClear, func(tx Store) error {
+		tasks, _ := tx.List()
+		for _, task := range tasks {
+			if err := tx.Delete(task.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ts.Undo(false); err != nil {
+		t.Fatalf("Unexpected error undoing clear: %v", err)
+	}
+	if got := len(ts.ListTasks()); got != 3 {
+		t.Errorf("Expected 3 tasks after undoing clear, got %d", got)
+	}
+}
+
+func TestTaskStorage_UndoConflict(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	added, err := ts.AddTask("Task", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.UpdateTask(ctx, added.ID, TaskPatch{Title: ptr("Edited")}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Change the task behind the journal's back
+	err = ts.transaction("", func(tx Store) error {
+		task, _ := tx.Get(added.ID)
+		task.Title = "Edited elsewhere"
+		task.UpdatedAt = task.UpdatedAt.Add(1)
+		return tx.Put(task)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ts.Undo(false); !errors.Is(err, ErrJournalConflict) {
+		t.Fatalf("Expected ErrJournalConflict, got %v", err)
+	}
+	if _, err := ts.Undo(true); err != nil {
+		t.Fatalf("Expected forced undo to succeed, got %v", err)
+	}
+	task, _ := ts.GetTask(added.ID)
+	if task.Title != "Task" {
+		t.Errorf("Expected forced undo to restore the title, got %q", task.Title)
+	}
+}
@@ -0,0 +1,342 @@
+package task
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/config"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/fsutil"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/logger"
+	"go.uber.org/zap"
+)
+
+// jsonStore keeps all tasks in a single JSON file which is rewritten on every change.
+// The sequence of issued IDs is kept in a "<file>.seq" sidecar file, so the
+// tasks file stays a plain array.
+//
+// Every load-modify-save cycle holds an advisory lock on a sidecar
+// "<file>.lock" file, so concurrent CLI invocations don't overwrite each
+// other's changes. The in-process mutex alone only covers goroutines.
+type jsonStore struct {
+	mu             sync.Mutex
+	filePath       string
+	backupDir      string        // Where corrupt files are quarantined
+	recoverCorrupt bool          // Continue with an empty list after quarantining
+	lockTimeout    time.Duration // How long to wait for other processes
+	tasks          taskList
+	sequence       int // Highest sequential ID issued
+}
+
+func newJSONStore(cfg config.StorageConfig) (*jsonStore, error) {
+	if cfg.FilePath == "" {
+		return nil, fmt.Errorf("storage file path cannot be empty")
+	}
+
+	s := &jsonStore{
+		filePath:       cfg.FilePath,
+		backupDir:      cfg.BackupDir,
+		recoverCorrupt: cfg.RecoverCorrupt,
+		lockTimeout:    cfg.LockTimeout,
+		tasks:          taskList{},
+	}
+
+	if err := s.withFileLock(s.loadFromFile); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *jsonStore) Get(id string) (Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tasks.Get(id)
+}
+
+func (s *jsonStore) List() ([]Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tasks.List()
+}
+
+func (s *jsonStore) Put(t Task) error {
+	return s.Transaction(func(tx Store) error {
+		return tx.Put(t)
+	})
+}
+
+func (s *jsonStore) Delete(id string) error {
+	return s.Transaction(func(tx Store) error {
+		return tx.Delete(id)
+	})
+}
+
+func (s *jsonStore) Sequence() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sequence, nil
+}
+
+func (s *jsonStore) SetSequence(n int) error {
+	return s.Transaction(func(tx Store) error {
+		return tx.SetSequence(n)
+	})
+}
+
+// Transaction locks the file, reloads it, applies fn to a copy of its tasks
+// and rewrites the file once if fn succeeds.
+func (s *jsonStore) Transaction(fn func(tx Store) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.withFileLock(func() error {
+		return s.transaction(fn)
+	})
+}
+
+func (s *jsonStore) transaction(fn func(tx Store) error) error {
+	if err := s.loadFromFile(); err != nil {
+		return err
+	}
+
+	tx := &jsonTx{tasks: append(taskList{}, s.tasks...), sequence: s.sequence}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	// The sequence is saved first: if saving the tasks fails afterwards, an
+	// ID is skipped rather than issued twice
+	if tx.sequence != s.sequence {
+		if err := s.saveSequence(tx.sequence); err != nil {
+			return err
+		}
+		s.sequence = tx.sequence
+	}
+
+	// Read-only transactions, such as taking a backup, leave the file alone
+	if !tx.changed {
+		return nil
+	}
+
+	previous := s.tasks
+	s.tasks = tx.tasks
+	if err := s.saveToFile(); err != nil {
+		s.tasks = previous
+		return err
+	}
+
+	return nil
+}
+
+func (s *jsonStore) Close() error {
+	return nil
+}
+
+// withFileLock runs fn while holding the cross-process lock of the tasks file
+func (s *jsonStore) withFileLock(fn func() error) error {
+	lockPath := s.filePath + ".lock"
+
+	lock, err := fsutil.AcquireLock(lockPath, s.lockTimeout)
+	if errors.Is(err, fsutil.ErrLockTimeout) {
+		return fmt.Errorf("%w (waited %s for %s)", ErrStorageLocked, s.lockTimeout, lockPath)
+	}
+	if err != nil {
+		return fmt.Errorf("error locking tasks file: %w", err)
+	}
+	defer lock.Release()
+
+	return fn()
+}
+
+func (s *jsonStore) loadFromFile() error {
+	// A quarantined file that wasn't repaired must not be replaced by an
+	// empty one on the next run
+	if s.recoverCorrupt {
+		if err := ResolveQuarantine(s.filePath); err != nil {
+			return err
+		}
+	} else if err := unresolvedQuarantine(s.filePath); err != nil {
+		return err
+	}
+	if err := s.loadSequence(); err != nil {
+		return err
+	}
+	if _, err := os.Stat(s.filePath); err != nil {
+		if os.IsNotExist(err) {
+			s.tasks = taskList{}
+			return s.saveToFile()
+		}
+		return fmt.Errorf("checking file status: %w", err)
+	}
+
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		return fmt.Errorf("error reading the file: %v", err)
+	}
+
+	if len(data) == 0 {
+		logger.Debug("tasks file is empty, creating a new one", zap.String("file", s.filePath))
+		s.tasks = taskList{}
+		return s.saveToFile()
+	}
+
+	var tasks taskList
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		quarantined, qerr := quarantineFile(s.filePath, s.backupDir)
+		if qerr != nil {
+			return fmt.Errorf("error deserializing file, it may be corrupt (%v); quarantine failed: %w", err, qerr)
+		}
+
+		corrupt := &CorruptFileError{Path: s.filePath, QuarantinePath: quarantined, Err: err}
+		if !s.recoverCorrupt {
+			if merr := writeQuarantineMarker(s.filePath, quarantined, err); merr != nil {
+				logger.Error("recording the quarantined file failed", zap.String("file", s.filePath), zap.Error(merr))
+			}
+			return corrupt
+		}
+
+		logger.Error("corrupt tasks file quarantined, starting with an empty list",
+			zap.String("file", s.filePath), zap.String("quarantine", quarantined), zap.Error(err))
+
+		s.tasks = taskList{}
+		return s.saveToFile()
+	}
+	if tasks == nil {
+		tasks = taskList{}
+	}
+	for i := range tasks {
+		tasks[i].applyDefaults()
+	}
+	s.tasks = tasks
+
+	return nil
+}
+
+func (s *jsonStore) saveToFile() error {
+	data, err := json.MarshalIndent(s.tasks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing tasks: %v", err)
+	}
+
+	err = fsutil.WriteFile(s.filePath, data, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing on the file: %v", err)
+	}
+
+	logger.Debug("tasks saved", zap.String("file", s.filePath), zap.Int("count", len(s.tasks)))
+
+	return nil
+}
+
+func (s *jsonStore) sequencePath() string {
+	return s.filePath + ".seq"
+}
+
+// loadSequence reads the sidecar sequence file, a missing file meaning that
+// no sequential ID was recorded yet
+func (s *jsonStore) loadSequence() error {
+	data, err := os.ReadFile(s.sequencePath())
+	if os.IsNotExist(err) {
+		s.sequence = 0
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading the ID sequence: %v", err)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return fmt.Errorf("invalid ID sequence in %s: %v", s.sequencePath(), err)
+	}
+	s.sequence = n
+	return nil
+}
+
+func (s *jsonStore) saveSequence(n int) error {
+	if err := fsutil.WriteFile(s.sequencePath(), []byte(strconv.Itoa(n)+"\n"), 0644); err != nil {
+		return fmt.Errorf("error writing the ID sequence: %v", err)
+	}
+	return nil
+}
+
+// jsonTx is the in-memory view handed to jsonStore transactions
+type jsonTx struct {
+	tasks    taskList
+	sequence int
+	changed  bool // Whether tasks were put or deleted
+}
+
+func (tx *jsonTx) Get(id string) (Task, error) { return tx.tasks.Get(id) }
+func (tx *jsonTx) List() ([]Task, error)       { return tx.tasks.List() }
+func (tx *jsonTx) Sequence() (int, error)      { return tx.sequence, nil }
+func (tx *jsonTx) SetSequence(n int) error     { tx.sequence = n; return nil }
+func (tx *jsonTx) Close() error                { return nil }
+
+func (tx *jsonTx) Put(t Task) error {
+	tx.tasks = tx.tasks.Put(t)
+	tx.changed = true
+	return nil
+}
+
+func (tx *jsonTx) Delete(id string) error {
+	tasks, err := tx.tasks.Delete(id)
+	if err != nil {
+		return err
+	}
+	tx.tasks = tasks
+	tx.changed = true
+	return nil
+}
+
+// Transaction runs fn within the enclosing transaction
+func (tx *jsonTx) Transaction(fn func(tx Store) error) error {
+	return fn(tx)
+}
+
+// taskList is an ordered slice of tasks with ID based accessors
+type taskList []Task
+
+func (l taskList) index(id string) int {
+	for i := range l {
+		if l[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (l taskList) Get(id string) (Task, error) {
+	if i := l.index(id); i >= 0 {
+		return l[i], nil
+	}
+	return Task{}, ErrTaskNotFound
+}
+
+func (l taskList) List() ([]Task, error) {
+	return append([]Task{}, l...), nil
+}
+
+func (l taskList) Put(t Task) taskList {
+	if i := l.index(t.ID); i >= 0 {
+		l[i] = t
+		return l
+	}
+	return append(l, t)
+}
+
+func (l taskList) Delete(id string) (taskList, error) {
+	i := l.index(id)
+	if i < 0 {
+		return l, ErrTaskNotFound
+	}
+	return append(l[:i], l[i+1:]...), nil
+}
@@ -0,0 +1,134 @@
+package task
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/config"
+)
+
+// ErrValidation is matched by every ValidationError
+var ErrValidation = errors.New("validation failed")
+
+// ValidationError reports a task field holding an invalid value
+type ValidationError struct {
+	Field   string // Name of the offending field
+	Message string // Human readable reason
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+// TaskPatch is a partial update of a task. Nil fields are left unchanged.
+type TaskPatch struct {
+	Title       *string
+	Description *string
+	Status      *Status        // Status names and aliases are both accepted
+	Priority    *Priority      // Priority names and aliases are both accepted
+	DueAt       *time.Time     // A zero time clears the due date
+	Reminder    *time.Duration // Zero clears the reminder
+	AddTags     []string       // Tags added to the task
+	RemoveTags  []string       // Tags removed from the task, applied after AddTags
+	ParentID    *string        // An empty ID makes the task a top-level task
+	Estimate    *time.Duration // Zero clears the estimate
+	Recurrence  *Recurrence    // A rule without frequency stops the recurrence
+	Force       bool           // Allow status changes the workflow forbids
+}
+
+// IsEmpty reports whether the patch changes nothing
+func (p TaskPatch) IsEmpty() bool {
+	return p.Title == nil && p.Description == nil && p.Status == nil && p.Priority == nil && p.DueAt == nil &&
+		len(p.AddTags) == 0 && len(p.RemoveTags) == 0 && p.ParentID == nil && p.Estimate == nil &&
+		p.Reminder == nil && p.Recurrence == nil
+}
+
+// Apply returns a copy of t with the patch applied and validated against cfg.
+// t itself is never modified, so a failed patch leaves no partial changes.
+func (p TaskPatch) Apply(t Task, cfg config.TaskConfig) (Task, error) {
+	if p.IsEmpty() {
+		return t, ErrNoUpdatesProvided
+	}
+
+	if p.Title != nil {
+		t.Title = *p.Title
+		if t.Title == "" {
+			t.Title = "Untitled Task"
+		}
+	}
+	if p.Description != nil {
+		t.Description = *p.Description
+	}
+	if p.Status != nil {
+		workflow, err := workflowOf(cfg)
+		if err != nil {
+			return t, err
+		}
+		status, err := workflow.ValidateStatus(string(*p.Status))
+		if err != nil {
+			return t, err
+		}
+		t.Status = status
+	}
+	if p.Priority != nil {
+		priority, err := ValidatePriority(string(*p.Priority))
+		if err != nil {
+			return t, err
+		}
+		t.Priority = priority
+	}
+	if p.DueAt != nil {
+		if p.DueAt.IsZero() {
+			t.DueAt = nil
+		} else {
+			due := *p.DueAt
+			t.DueAt = &due
+		}
+	}
+	if p.Reminder != nil {
+		if *p.Reminder < 0 {
+			return t, &ValidationError{Field: "reminder", Message: "reminder cannot be negative"}
+		}
+		t.Reminder = *p.Reminder
+	}
+	if len(p.AddTags) > 0 || len(p.RemoveTags) > 0 {
+		tags, err := normalizeTags(append(append([]string(nil), t.Tags...), p.AddTags...))
+		if err != nil {
+			return t, err
+		}
+		for _, tag := range p.RemoveTags {
+			tag, err := NormalizeTag(tag)
+			if err != nil {
+				return t, err
+			}
+			tags = removeTag(tags, tag)
+		}
+		t.Tags = tags
+	}
+	if p.ParentID != nil {
+		t.ParentID = *p.ParentID
+	}
+	if p.Estimate != nil {
+		if *p.Estimate < 0 {
+			return t, &ValidationError{Field: "estimate", Message: "estimate cannot be negative"}
+		}
+		t.Estimate = *p.Estimate
+	}
+	if p.Recurrence != nil {
+		if p.Recurrence.Freq == "" {
+			t.Recurrence = nil
+		} else {
+			rule := *p.Recurrence
+			t.Recurrence = &rule
+		}
+	}
+
+	if err := t.ValidateWithConfig(cfg); err != nil {
+		return t, err
+	}
+	return t, nil
+}
@@ -0,0 +1,67 @@
+package task
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Priority represents the importance of a task
+type Priority string
+
+const (
+	// Priorities
+	PriorityLow    Priority = "LOW"
+	PriorityMedium Priority = "MEDIUM"
+	PriorityHigh   Priority = "HIGH"
+	PriorityUrgent Priority = "URGENT"
+)
+
+var (
+	// Priority aliases
+	PriorityAliases = map[string]Priority{
+		"low":    PriorityLow,
+		"l":      PriorityLow,
+		"medium": PriorityMedium,
+		"med":    PriorityMedium,
+		"m":      PriorityMedium,
+		"high":   PriorityHigh,
+		"h":      PriorityHigh,
+		"urgent": PriorityUrgent,
+		"u":      PriorityUrgent,
+	}
+
+	priorityRanks = map[Priority]int{
+		PriorityLow:    0,
+		PriorityMedium: 1,
+		PriorityHigh:   2,
+		PriorityUrgent: 3,
+	}
+)
+
+// ValidatePriority validates a priority name or alias
+func ValidatePriority(s string) (Priority, error) {
+	if priority, exists := PriorityAliases[strings.ToLower(s)]; exists {
+		return priority, nil
+	}
+	return "", &ValidationError{Field: "priority", Message: fmt.Sprintf("invalid priority: %s. Use one of: low/l, medium/med/m, high/h, urgent/u", s)}
+}
+
+// Rank orders priorities from low (0) to urgent (3)
+func (p Priority) Rank() int {
+	if rank, ok := priorityRanks[p]; ok {
+		return rank
+	}
+	return priorityRanks[PriorityMedium]
+}
+
+// SortTasks orders tasks by priority, most important first, then by age,
+// oldest first. The sort is stable so equal tasks keep their order.
+func SortTasks(tasks []Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if ri, rj := tasks[i].Priority.Rank(), tasks[j].Priority.Rank(); ri != rj {
+			return ri > rj
+		}
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+}
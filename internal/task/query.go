@@ -0,0 +1,451 @@
+package task
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Query is a parsed filter expression such as
+//
+//	status:todo and (tag:backend or priority>=high) and created>2026-01-01 and title~"deploy"
+//
+// Conditions have the form <field><operator><value> and are combined with
+// and, or, not and parentheses; and binds tighter than or. Supported fields:
+//
+//	id, title, description (desc)   : = != ~ (~ is a case-insensitive substring match)
+//	status                          : = !=, names and aliases
+//	priority                        : = != > >= < <=, names and aliases
+//	tag                             : = != ~
+//	created, updated, due           : = != > >= < <=, dates as accepted by ParseDate
+//
+// ":" is a synonym of "=". Text comparisons ignore case. due:none matches tasks
+// without a due date. Dates without a time of day cover the whole day, so
+// created>2026-01-01 matches tasks created from January 2nd on.
+type Query struct {
+	src  string
+	root queryNode
+}
+
+// queryNode is a node of the query AST
+type queryNode interface {
+	eval(t Task) bool
+}
+
+type andNode struct{ left, right queryNode }
+type orNode struct{ left, right queryNode }
+type notNode struct{ expr queryNode }
+
+// condNode is a single <field><op><value> comparison, compiled to match
+type condNode struct {
+	field, op, value string
+	match            func(t Task) bool
+}
+
+func (n andNode) eval(t Task) bool  { return n.left.eval(t) && n.right.eval(t) }
+func (n orNode) eval(t Task) bool   { return n.left.eval(t) || n.right.eval(t) }
+func (n notNode) eval(t Task) bool  { return !n.expr.eval(t) }
+func (n condNode) eval(t Task) bool { return n.match(t) }
+
+// ParseQuery parses a filter expression. Statuses are those of workflow w,
+// dates are parsed with layout and phrases such as "tomorrow" are resolved
+// relative to now. An empty expression matches every task. Syntax errors are
+// *ValidationError.
+func ParseQuery(src, layout string, w *Workflow, now time.Time) (*Query, error) {
+	p := &queryParser{lex: queryLexer{src: src}, layout: layout, workflow: w, now: now}
+	if err := p.advance(false); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokEOF {
+		return &Query{src: src}, nil
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	return &Query{src: src, root: root}, nil
+}
+
+// Match reports whether the task satisfies the query
+func (q *Query) Match(t Task) bool {
+	return q.root == nil || q.root.eval(t)
+}
+
+// String returns the source of the query
+func (q *Query) String() string {
+	return q.src
+}
+
+// Query returns the tasks matching the filter expression, see Query for the syntax
+func (ts *TaskStorage) Query(where string) ([]Task, error) {
+	q, err := ParseQuery(where, ts.dateFormat(), ts.workflow, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	var matched []Task
+	for _, task := range ts.tasks {
+		if q.Match(task) {
+			matched = append(matched, task)
+		}
+	}
+	return matched, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// queryLexer splits a query into tokens. Values are lexed in a separate mode
+// so that they may contain operator characters, e.g. due<2026-01-01T10:00:00Z.
+type queryLexer struct {
+	src string
+	pos int
+}
+
+func isOpChar(r byte) bool {
+	return strings.IndexByte(":=!<>~", r) >= 0
+}
+
+func (l *queryLexer) next(value bool) (token, error) {
+	for l.pos < len(l.src) && unicode.IsSpace(rune(l.src[l.pos])) {
+		l.pos++
+	}
+	start := l.pos
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, pos: start}, nil
+	}
+
+	switch c := l.src[l.pos]; {
+	case c == '(' && !value:
+		l.pos++
+		return token{kind: tokLParen, text: "(", pos: start}, nil
+	case c == ')' && !value:
+		l.pos++
+		return token{kind: tokRParen, text: ")", pos: start}, nil
+	case c == '"':
+		var b strings.Builder
+		for l.pos++; l.pos < len(l.src); l.pos++ {
+			switch l.src[l.pos] {
+			case '\\':
+				if l.pos+1 < len(l.src) {
+					l.pos++
+					b.WriteByte(l.src[l.pos])
+				}
+			case '"':
+				l.pos++
+				return token{kind: tokString, text: b.String(), pos: start}, nil
+			default:
+				b.WriteByte(l.src[l.pos])
+			}
+		}
+		return token{}, queryError(start, "unterminated string")
+	case isOpChar(c) && !value:
+		for l.pos < len(l.src) && isOpChar(l.src[l.pos]) {
+			l.pos++
+		}
+		return token{kind: tokOp, text: l.src[start:l.pos], pos: start}, nil
+	}
+
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if unicode.IsSpace(rune(c)) || c == '(' || c == ')' || c == '"' || (!value && isOpChar(c)) {
+			break
+		}
+		l.pos++
+	}
+	return token{kind: tokWord, text: l.src[start:l.pos], pos: start}, nil
+}
+
+func queryError(pos int, format string, args ...any) error {
+	return &ValidationError{
+		Field:   "query",
+		Message: fmt.Sprintf("invalid query at position %d: %s", pos+1, fmt.Sprintf(format, args...)),
+	}
+}
+
+type queryParser struct {
+	lex      queryLexer
+	tok      token
+	layout   string
+	workflow *Workflow
+	now      time.Time
+}
+
+func (p *queryParser) advance(value bool) error {
+	tok, err := p.lex.next(value)
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *queryParser) errorf(format string, args ...any) error {
+	return queryError(p.tok.pos, format, args...)
+}
+
+func (p *queryParser) isKeyword(word string) bool {
+	return p.tok.kind == tokWord && strings.EqualFold(p.tok.text, word)
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") {
+		if err := p.advance(false); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") {
+		if err := p.advance(false); err != nil {
+			return nil, err
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseNot() (queryNode, error) {
+	if p.isKeyword("not") {
+		if err := p.advance(false); err != nil {
+			return nil, err
+		}
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{expr}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (queryNode, error) {
+	switch p.tok.kind {
+	case tokLParen:
+		if err := p.advance(false); err != nil {
+			return nil, err
+		}
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, p.errorf("expected \")\", got %s", p.tok)
+		}
+		return expr, p.advance(false)
+	case tokWord:
+		return p.parseCondition()
+	default:
+		return nil, p.errorf("expected a condition, got %s", p.tok)
+	}
+}
+
+func (p *queryParser) parseCondition() (queryNode, error) {
+	field := p.tok
+	if err := p.advance(false); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokOp {
+		return nil, p.errorf("expected an operator after %q, got %s", field.text, p.tok)
+	}
+	op := p.tok
+	if err := p.advance(true); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokWord && p.tok.kind != tokString {
+		return nil, p.errorf("expected a value after %q, got %s", field.text+op.text, p.tok)
+	}
+	value := p.tok.text
+
+	match, err := p.compile(strings.ToLower(field.text), op.text, value)
+	if err != nil {
+		return nil, queryError(field.pos, "%s", err)
+	}
+	if err := p.advance(false); err != nil {
+		return nil, err
+	}
+	return condNode{field: field.text, op: op.text, value: value, match: match}, nil
+}
+
+// compile turns a single comparison into a matcher
+func (p *queryParser) compile(field, op, value string) (func(Task) bool, error) {
+	if op == ":" {
+		op = "="
+	}
+
+	switch field {
+	case "id":
+		return compileText(op, value, func(t Task) string { return t.ID })
+	case "title":
+		return compileText(op, value, func(t Task) string { return t.Title })
+	case "description", "desc":
+		return compileText(op, value, func(t Task) string { return t.Description })
+	case "status":
+		status, err := p.workflow.ValidateStatus(value)
+		if err != nil {
+			return nil, err
+		}
+		return compileOrdered(op, []string{"=", "!="}, func(t Task) int {
+			return strings.Compare(string(t.Status), string(status))
+		})
+	case "priority":
+		priority, err := ValidatePriority(value)
+		if err != nil {
+			return nil, err
+		}
+		return compileOrdered(op, nil, func(t Task) int {
+			return t.Priority.Rank() - priority.Rank()
+		})
+	case "tag", "tags":
+		tag := strings.ToLower(value)
+		switch op {
+		case "=":
+			return func(t Task) bool { return t.HasTag(tag) }, nil
+		case "!=":
+			return func(t Task) bool { return !t.HasTag(tag) }, nil
+		case "~":
+			return func(t Task) bool {
+				for _, own := range t.Tags {
+					if strings.Contains(own, tag) {
+						return true
+					}
+				}
+				return false
+			}, nil
+		}
+		return nil, fmt.Errorf("operator %q is not supported for tag", op)
+	case "created":
+		return p.compileDate(op, value, func(t Task) *time.Time { return &t.CreatedAt })
+	case "updated":
+		return p.compileDate(op, value, func(t Task) *time.Time { return &t.UpdatedAt })
+	case "due":
+		if strings.EqualFold(value, "none") && (op == "=" || op == "!=") {
+			want := op == "="
+			return func(t Task) bool { return (t.DueAt == nil) == want }, nil
+		}
+		return p.compileDate(op, value, func(t Task) *time.Time { return t.DueAt })
+	}
+
+	return nil, fmt.Errorf("unknown field %q", field)
+}
+
+func compileText(op, value string, get func(Task) string) (func(Task) bool, error) {
+	value = strings.ToLower(value)
+	switch op {
+	case "=":
+		return func(t Task) bool { return strings.ToLower(get(t)) == value }, nil
+	case "!=":
+		return func(t Task) bool { return strings.ToLower(get(t)) != value }, nil
+	case "~":
+		return func(t Task) bool { return strings.Contains(strings.ToLower(get(t)), value) }, nil
+	}
+	return nil, fmt.Errorf("operator %q is not supported for text fields", op)
+}
+
+// compileOrdered builds a matcher from cmp, which returns the sign of the
+// task's value compared to the query value. allowed restricts the operators
+// accepted; nil allows all comparisons.
+func compileOrdered(op string, allowed []string, cmp func(Task) int) (func(Task) bool, error) {
+	if allowed != nil && !slices.Contains(allowed, op) {
+		return nil, fmt.Errorf("operator %q is not supported here, use one of %s", op, strings.Join(allowed, " "))
+	}
+
+	var test func(int) bool
+	switch op {
+	case "=":
+		test = func(c int) bool { return c == 0 }
+	case "!=":
+		test = func(c int) bool { return c != 0 }
+	case ">":
+		test = func(c int) bool { return c > 0 }
+	case ">=":
+		test = func(c int) bool { return c >= 0 }
+	case "<":
+		test = func(c int) bool { return c < 0 }
+	case "<=":
+		test = func(c int) bool { return c <= 0 }
+	default:
+		return nil, fmt.Errorf("unknown operator %q", op)
+	}
+	return func(t Task) bool { return test(cmp(t)) }, nil
+}
+
+// compileDate compares a date field against value. Values without a time of
+// day cover the whole day. Tasks without the date never match.
+func (p *queryParser) compileDate(op, value string, get func(Task) *time.Time) (func(Task) bool, error) {
+	date, err := ParseDate(value, p.layout, p.now)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := date, date
+	if date.Equal(endOfDay(date)) {
+		y, m, d := date.Date()
+		start = time.Date(y, m, d, 0, 0, 0, 0, date.Location())
+		end = start.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	match, err := compileOrdered(op, nil, func(t Task) int {
+		at := get(t)
+		switch {
+		case at.Before(start):
+			return -1
+		case at.After(end):
+			return 1
+		}
+		return 0
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func(t Task) bool { return get(t) != nil && match(t) }, nil
+}
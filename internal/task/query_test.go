@@ -0,0 +1,103 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	now := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC)
+	tasks := map[string]Task{
+		"deploy": {Title: "Deploy API", Status: StatusTodo, Priority: PriorityHigh, Tags: []string{"ops"},
+			CreatedAt: time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC), DueAt: &due},
+		"backend": {Title: "Fix cache", Status: StatusTodo, Priority: PriorityLow, Tags: []string{"backend"},
+			CreatedAt: time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)},
+		"done": {Title: "Deploy docs", Status: StatusDone, Priority: PriorityUrgent, Tags: []string{"backend"},
+			CreatedAt: time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)},
+	}
+
+	scenarios := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{"Empty query", "", []string{"backend", "deploy", "done"}},
+		{"Status alias", "status:t", []string{"backend", "deploy"}},
+		{"Priority comparison", "priority>=high", []string{"deploy", "done"}},
+		{"Tag", "tag:backend", []string{"backend", "done"}},
+		{"Date excludes the whole day", "created>2026-01-01", []string{"deploy"}},
+		{"Date on day", "created:2026-01-01", []string{"backend"}},
+		{"Title contains", `title~"deploy"`, []string{"deploy", "done"}},
+		{"Due phrase", "due<=friday", []string{"deploy"}},
+		{"No due date", "due:none", []string{"backend", "done"}},
+		{"Not", "not tag:backend", []string{"deploy"}},
+		{"And binds tighter than or", "status:done or tag:ops and priority:high", []string{"deploy", "done"}},
+		{"Example from the docs", `status:todo and (tag:backend or priority>=high) and created>2026-01-01 and title~"deploy"`, []string{"deploy"}},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			q, err := ParseQuery(scenario.query, time.RFC3339, DefaultWorkflow, now)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var matched []string
+			for _, name := range []string{"backend", "deploy", "done"} {
+				if q.Match(tasks[name]) {
+					matched = append(matched, name)
+				}
+			}
+			if len(matched) != len(scenario.expected) {
+				t.Fatalf("Expected %v, got %v", scenario.expected, matched)
+			}
+			for i := range matched {
+				if matched[i] != scenario.expected[i] {
+					t.Fatalf("Expected %v, got %v", scenario.expected, matched)
+				}
+			}
+		})
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	for _, query := range []string{
+		"status",
+		"status:",
+		"status:unknown",
+		"color:red",
+		"priority~high",
+		"(status:todo",
+		"status:todo and",
+		`title~"deploy`,
+		"status:todo tag:x",
+	} {
+		t.Run(query, func(t *testing.T) {
+			_, err := ParseQuery(query, time.RFC3339, DefaultWorkflow, time.Now())
+			if !errors.Is(err, ErrValidation) {
+				t.Errorf("Expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTaskStorage_Query(t *testing.T) {
+	ts, _ := setupTestStorage(t)
+
+	if _, err := ts.AddTask("Deploy", "", WithTags("ops")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.AddTask("Write docs", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks, err := ts.Query("tag:ops")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Title != "Deploy" {
+		t.Errorf("Expected only the ops task, got %+v", tasks)
+	}
+}
@@ -0,0 +1,333 @@
+package task
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is how often a recurring task repeats
+type Frequency string
+
+const (
+	FreqDaily   Frequency = "DAILY"
+	FreqWeekly  Frequency = "WEEKLY"
+	FreqMonthly Frequency = "MONTHLY"
+)
+
+// Recurrence is the rule a recurring task repeats by. It is stored as a
+// subset of the iCalendar RRULE syntax, e.g. "FREQ=WEEKLY;BYDAY=MO,TH".
+type Recurrence struct {
+	Freq     Frequency
+	Interval int            // Every Interval days, weeks or months, at least 1
+	Weekdays []time.Weekday // WEEKLY only: days of the week, none for the weekday of the due date
+	MonthDay int            // MONTHLY only: day of the month, 0 for the day of the due date. Short months use their last day.
+
+	raw string // Rule read from a file that failed to parse, kept as is
+	err error  // Why raw failed to parse, see Validate
+}
+
+// rruleDays are the RRULE names of the days of the week, indexed by time.Weekday
+var rruleDays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// ParseRecurrence parses a recurrence rule, which is one of
+//
+//	daily
+//	weekly                  on the weekday of the due date
+//	weekly:mon,thu          on the given days of the week
+//	monthly                 on the day of the month of the due date
+//	monthly:15              on the given day of the month
+//	FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH
+//
+// The RRULE form, optionally prefixed with "RRULE:", supports FREQ (DAILY,
+// WEEKLY or MONTHLY), INTERVAL, BYDAY and BYMONTHDAY.
+func ParseRecurrence(s string) (*Recurrence, error) {
+	input := strings.TrimSpace(s)
+	if strings.Contains(input, "=") {
+		return parseRRule(s, strings.TrimPrefix(strings.ToUpper(input), "RRULE:"))
+	}
+
+	name, arg, hasArg := strings.Cut(strings.ToLower(input), ":")
+	r := &Recurrence{Interval: 1}
+	switch name {
+	case "daily":
+		r.Freq = FreqDaily
+		if hasArg {
+			return nil, recurrenceError(s)
+		}
+	case "weekly":
+		r.Freq = FreqWeekly
+		if hasArg {
+			for _, day := range strings.Split(arg, ",") {
+				weekday, ok := parseWeekday(day)
+				if !ok {
+					return nil, recurrenceError(s)
+				}
+				r.Weekdays = append(r.Weekdays, weekday)
+			}
+		}
+	case "monthly":
+		r.Freq = FreqMonthly
+		if hasArg {
+			day, err := parseMonthDay(arg)
+			if err != nil {
+				return nil, recurrenceError(s)
+			}
+			r.MonthDay = day
+		}
+	default:
+		return nil, recurrenceError(s)
+	}
+	r.normalize()
+	return r, nil
+}
+
+func parseRRule(s, rule string) (*Recurrence, error) {
+	r := &Recurrence{Interval: 1}
+	var byDay, byMonthDay string
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, recurrenceError(s)
+		}
+		switch key {
+		case "FREQ":
+			r.Freq = Frequency(value)
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, recurrenceError(s)
+			}
+			r.Interval = n
+		case "BYDAY":
+			byDay = value
+		case "BYMONTHDAY":
+			byMonthDay = value
+		default:
+			return nil, &ValidationError{Field: "recurrence", Message: fmt.Sprintf("unsupported recurrence rule part %s in %q. Use FREQ, INTERVAL, BYDAY or BYMONTHDAY", key, s)}
+		}
+	}
+
+	switch {
+	case r.Freq != FreqDaily && r.Freq != FreqWeekly && r.Freq != FreqMonthly:
+		return nil, recurrenceError(s)
+	case byDay != "" && r.Freq != FreqWeekly, byMonthDay != "" && r.Freq != FreqMonthly:
+		return nil, recurrenceError(s)
+	}
+	if byDay != "" {
+		for _, day := range strings.Split(byDay, ",") {
+			weekday := slices.Index(rruleDays, strings.TrimSpace(day))
+			if weekday < 0 {
+				return nil, recurrenceError(s)
+			}
+			r.Weekdays = append(r.Weekdays, time.Weekday(weekday))
+		}
+	}
+	if byMonthDay != "" {
+		day, err := parseMonthDay(byMonthDay)
+		if err != nil {
+			return nil, recurrenceError(s)
+		}
+		r.MonthDay = day
+	}
+	r.normalize()
+	return r, nil
+}
+
+func recurrenceError(s string) error {
+	return &ValidationError{Field: "recurrence", Message: fmt.Sprintf("invalid recurrence: %q. Use daily, weekly, weekly:mon,thu, monthly, monthly:15 or an RRULE such as FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", s)}
+}
+
+// parseWeekday accepts the day names of due dates, e.g. "mon" or "monday",
+// and the two letter RRULE names, e.g. "mo"
+func parseWeekday(s string) (time.Weekday, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if day, ok := weekdays[s]; ok {
+		return day, true
+	}
+	day := slices.Index(rruleDays, strings.ToUpper(s))
+	return time.Weekday(day), day >= 0
+}
+
+func parseMonthDay(s string) (int, error) {
+	day, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || day < 1 || day > 31 {
+		return 0, fmt.Errorf("invalid day of the month %q", s)
+	}
+	return day, nil
+}
+
+// normalize sorts the weekdays and drops duplicates, so equal rules have the same string
+func (r *Recurrence) normalize() {
+	slices.Sort(r.Weekdays)
+	r.Weekdays = slices.Compact(r.Weekdays)
+}
+
+// Validate reports a rule read from a file that could not be parsed
+func (r Recurrence) Validate() error {
+	return r.err
+}
+
+// String returns the rule in the RRULE syntax, or as it was read when it could
+// not be parsed
+func (r Recurrence) String() string {
+	if r.err != nil {
+		return r.raw
+	}
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.Weekdays) > 0 {
+		days := make([]string, len(r.Weekdays))
+		for i, d := range r.Weekdays {
+			days[i] = rruleDays[d]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.MonthDay > 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.MonthDay))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Describe returns the rule in words, e.g. "every 2 weeks on Mon, Thu"
+func (r Recurrence) Describe() string {
+	if r.err != nil {
+		return fmt.Sprintf("invalid rule %q", r.raw)
+	}
+	unit := map[Frequency]string{FreqDaily: "day", FreqWeekly: "week", FreqMonthly: "month"}[r.Freq]
+	s := "every " + unit
+	if r.Interval > 1 {
+		s = fmt.Sprintf("every %d %ss", r.Interval, unit)
+	}
+	if len(r.Weekdays) > 0 {
+		days := make([]string, len(r.Weekdays))
+		for i, d := range r.Weekdays {
+			days[i] = d.String()[:3]
+		}
+		s += " on " + strings.Join(days, ", ")
+	}
+	if r.MonthDay > 0 {
+		s += fmt.Sprintf(" on day %d", r.MonthDay)
+	}
+	return s
+}
+
+// MarshalText stores the rule in the RRULE syntax
+func (r Recurrence) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText reads a rule written by MarshalText. A rule that cannot be
+// parsed, e.g. after a hand edit, doesn't fail reading the whole file: it is
+// kept as is and reported by Validate and when the task recurs.
+func (r *Recurrence) UnmarshalText(data []byte) error {
+	parsed, err := ParseRecurrence(string(data))
+	if err != nil {
+		*r = Recurrence{raw: string(data), err: err}
+		return nil
+	}
+	*r = *parsed
+	return nil
+}
+
+// Next returns the first occurrence strictly after the given time, keeping its
+// time of day
+func (r Recurrence) Next(after time.Time) time.Time {
+	interval := max(r.Interval, 1)
+	switch r.Freq {
+	case FreqWeekly:
+		if len(r.Weekdays) == 0 {
+			return after.AddDate(0, 0, 7*interval)
+		}
+		// Weeks are counted from the week of after, so with an interval of 2
+		// the days of every other week are used
+		week := PeriodStart(PeriodWeek, after)
+		for d := 1; ; d++ {
+			next := after.AddDate(0, 0, d)
+			weeks := int(PeriodStart(PeriodWeek, next).Sub(week).Hours()/24+0.5) / 7
+			if weeks%interval == 0 && slices.Contains(r.Weekdays, next.Weekday()) {
+				return next
+			}
+		}
+	case FreqMonthly:
+		day := r.MonthDay
+		if day == 0 {
+			day = after.Day()
+		}
+		for months := 0; ; months += interval {
+			next := monthDay(after, months, day)
+			if next.After(after) {
+				return next
+			}
+		}
+	}
+	return after.AddDate(0, 0, interval)
+}
+
+// monthDay returns the given day of the month months after t, at the time of
+// day of t. Days past the end of the month use its last day.
+func monthDay(t time.Time, months, day int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, last)-1)
+}
+
+// WithRecurrence makes a new task repeat by r, see Recurrence
+func WithRecurrence(r *Recurrence) TaskOption {
+	return func(t *Task) {
+		t.Recurrence = r
+	}
+}
+
+// spawnNext adds the next occurrence of the recurring task t, completed at
+// now, and links the two. The occurrence copies the title, description,
+// priority, tags, reminder, estimate and parent of t and is due at the next occurrence
+// after the due date of t, skipping occurrences already past. A task without
+// due date repeats from the end of the day it was completed, so its next
+// occurrence is due on the first date of the rule after that day.
+func (ts *TaskStorage) spawnNext(tx Store, t *Task, now time.Time) error {
+	if err := t.Recurrence.Validate(); err != nil {
+		return err
+	}
+	from := endOfDay(now)
+	if t.DueAt != nil {
+		from = *t.DueAt
+	}
+	due := t.Recurrence.Next(from)
+	for !due.After(now) {
+		due = t.Recurrence.Next(due)
+	}
+
+	rule := *t.Recurrence
+	next := Task{
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      ts.workflow.Initial(),
+		Priority:    t.Priority,
+		DueAt:       &due,
+		Reminder:    t.Reminder,
+		Tags:        slices.Clone(t.Tags),
+		ParentID:    t.ParentID,
+		Estimate:    t.Estimate,
+		Recurrence:  &rule,
+		PreviousID:  t.ID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := assignID(tx, &next, ts.cfg.Task.IDStrategy); err != nil {
+		return err
+	}
+	t.NextID = next.ID
+	return tx.Put(next)
+}
+
+func formatRecurrence(r *Recurrence) string {
+	if r == nil {
+		return ""
+	}
+	return r.String()
+}
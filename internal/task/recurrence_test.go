@@ -0,0 +1,243 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"daily", "FREQ=DAILY"},
+		{"Weekly", "FREQ=WEEKLY"},
+		{"weekly:thu,mon,monday", "FREQ=WEEKLY;BYDAY=MO,TH"},
+		{"weekly:fr", "FREQ=WEEKLY;BYDAY=FR"},
+		{"monthly:15", "FREQ=MONTHLY;BYMONTHDAY=15"},
+		{"RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU", "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU"},
+		{"freq=monthly;bymonthday=1", "FREQ=MONTHLY;BYMONTHDAY=1"},
+	}
+	for _, tt := range tests {
+		r, err := ParseRecurrence(tt.input)
+		if err != nil {
+			t.Errorf("ParseRecurrence(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if got := r.String(); got != tt.want {
+			t.Errorf("ParseRecurrence(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"", "hourly", "daily:2", "weekly:xyz", "monthly:32", "FREQ=YEARLY",
+		"FREQ=DAILY;BYDAY=MO", "FREQ=WEEKLY;INTERVAL=0", "FREQ=WEEKLY;COUNT=3"} {
+		if _, err := ParseRecurrence(input); !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected a validation error for %q, got %v", input, err)
+		}
+	}
+}
+
+func TestRecurrence_Next(t *testing.T) {
+	// 2026-01-08 is a Thursday
+	at := func(month, day int) time.Time { return time.Date(2026, time.Month(month), day, 17, 0, 0, 0, time.UTC) }
+	rule := func(s string) Recurrence {
+		r, err := ParseRecurrence(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return *r
+	}
+
+	tests := []struct {
+		rule  string
+		after time.Time
+		want  time.Time
+	}{
+		{"daily", at(1, 8), at(1, 9)},
+		{"FREQ=DAILY;INTERVAL=3", at(1, 8), at(1, 11)},
+		{"weekly", at(1, 8), at(1, 15)},
+		{"weekly:mon,thu", at(1, 8), at(1, 12)},
+		{"weekly:mon,fri", at(1, 8), at(1, 9)},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", at(1, 8), at(1, 19)},
+		{"monthly", at(1, 8), at(2, 8)},
+		{"monthly:20", at(1, 8), at(1, 20)},
+		{"monthly:31", at(1, 31), at(2, 28)},
+		{"FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=1", at(1, 8), at(4, 1)},
+	}
+	for _, tt := range tests {
+		if got := rule(tt.rule).Next(tt.after); !got.Equal(tt.want) {
+			t.Errorf("%s after %s = %s, want %s", tt.rule, tt.after.Format(time.DateOnly), got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestRecurrence_JSON(t *testing.T) {
+	r, _ := ParseRecurrence("weekly:mon,thu")
+	data, err := json.Marshal(Task{ID: "1", Recurrence: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Task
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Decoding %s failed: %v", data, err)
+	}
+	if decoded.Recurrence == nil || decoded.Recurrence.String() != r.String() {
+		t.Errorf("Expected %s after a round trip, got %+v", r, decoded.Recurrence)
+	}
+}
+
+func TestTaskStorage_RecurringTask(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	weekly, _ := ParseRecurrence("weekly")
+	due := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	chore, err := ts.AddTask("Water plants", "Balcony too", WithRecurrence(weekly), WithDueAt(due),
+		WithTags("home"), WithPriority(PriorityHigh))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := StatusDone
+	completed, err := ts.UpdateTask(ctx, chore.ID, TaskPatch{Status: &done})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if completed.NextID == "" {
+		t.Fatal("Expected completing a recurring task to link its next occurrence")
+	}
+	next, err := ts.GetTask(completed.NextID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Title != chore.Title || next.Description != chore.Description || next.Priority != PriorityHigh ||
+		len(next.Tags) != 1 || next.Tags[0] != "home" || next.Status != StatusTodo {
+		t.Errorf("Expected a TODO copy of the task, got %+v", next)
+	}
+	if next.PreviousID != chore.ID || next.Recurrence == nil || next.Recurrence.String() != "FREQ=WEEKLY" {
+		t.Errorf("Expected the occurrence to repeat weekly after %s, got %+v", chore.ID, next)
+	}
+	if next.DueAt == nil || !next.DueAt.Equal(due.AddDate(0, 0, 7)) {
+		t.Errorf("Expected the occurrence due a week later, got %v", next.DueAt)
+	}
+
+	// Reopening and completing again does not add a second occurrence
+	todo := StatusTodo
+	if _, err := ts.UpdateTask(ctx, chore.ID, TaskPatch{Status: &todo}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.UpdateTask(ctx, chore.ID, TaskPatch{Status: &done}); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(ts.ListTasks()); n != 2 {
+		t.Errorf("Expected 2 tasks, got %d", n)
+	}
+
+	// Stopping the recurrence
+	if _, err := ts.UpdateTask(ctx, next.ID, TaskPatch{Recurrence: &Recurrence{}}); err != nil {
+		t.Fatal(err)
+	}
+	last, err := ts.UpdateTask(ctx, next.ID, TaskPatch{Status: &done})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.Recurrence != nil || last.NextID != "" || len(ts.ListTasks()) != 2 {
+		t.Errorf("Expected no occurrence once the recurrence stopped, got %+v", last)
+	}
+}
+
+func TestTaskStorage_RecurringTaskSkipsPastOccurrences(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	daily, _ := ParseRecurrence("daily")
+	due := time.Now().AddDate(0, 0, -10)
+	chore, _ := ts.AddTask("Stretch", "", WithRecurrence(daily), WithDueAt(due))
+
+	done := StatusDone
+	completed, err := ts.UpdateTask(context.Background(), chore.ID, TaskPatch{Status: &done})
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, _ := ts.GetTask(completed.NextID)
+	if next.DueAt == nil || !next.DueAt.After(time.Now()) || next.DueAt.After(time.Now().AddDate(0, 0, 1)) {
+		t.Errorf("Expected the next occurrence within a day, got %v", next.DueAt)
+	}
+}
+
+func TestRecurrence_InvalidRuleInFile(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	data := `[
+		{"id":"1","title":"Broken","status":"TODO","recurrence":"FREQ=YEARLY","created_at":"2026-03-01T09:00:00Z","updated_at":"2026-03-01T09:00:00Z"},
+		{"id":"2","title":"Fine","status":"TODO","created_at":"2026-03-01T09:00:00Z","updated_at":"2026-03-01T09:00:00Z"}
+	]`
+	if err := os.WriteFile(cfg.Storage.FilePath, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The bad rule doesn't make the file unreadable
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatalf("Expected the file to load, got %v", err)
+	}
+	broken, err := ts.GetTask("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if broken.Recurrence == nil || broken.Recurrence.String() != "FREQ=YEARLY" || !errors.Is(broken.Recurrence.Validate(), ErrValidation) {
+		t.Fatalf("Expected the invalid rule to be kept and reported, got %+v", broken.Recurrence)
+	}
+
+	// It is reported when the task is changed, until the rule is replaced
+	ctx := context.Background()
+	done := StatusDone
+	if _, err := ts.UpdateTask(ctx, "1", TaskPatch{Status: &done}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected a validation error completing the task, got %v", err)
+	}
+	daily, _ := ParseRecurrence("daily")
+	if _, err := ts.UpdateTask(ctx, "1", TaskPatch{Recurrence: daily}); err != nil {
+		t.Errorf("Expected replacing the rule to succeed, got %v", err)
+	}
+
+	stored, err := os.ReadFile(cfg.Storage.FilePath)
+	if err != nil || !strings.Contains(string(stored), `"FREQ=DAILY"`) {
+		t.Errorf("Expected the replaced rule to be saved, got %s (%v)", stored, err)
+	}
+}
+
+func TestTaskStorage_RecurringTaskWithoutDueDate(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	monthly, _ := ParseRecurrence("monthly:1")
+	rent, _ := ts.AddTask("Pay rent", "", WithRecurrence(monthly))
+
+	done := StatusDone
+	completed, err := ts.UpdateTask(context.Background(), rent.ID, TaskPatch{Status: &done})
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, _ := ts.GetTask(completed.NextID)
+
+	// The next occurrence is due on the next 1st after the day of completion
+	now := time.Now()
+	first := time.Date(now.Year(), now.Month()+1, 1, 23, 59, 59, 0, now.Location())
+	if next.DueAt == nil || !next.DueAt.Equal(first) {
+		t.Errorf("Expected the next occurrence due %v, got %v", first, next.DueAt)
+	}
+}
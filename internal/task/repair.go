@@ -0,0 +1,323 @@
+package task
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/internal/logger"
+	"go.uber.org/zap"
+)
+
+// quarantineSuffix separates the original file name from the quarantine timestamp
+const quarantineSuffix = ".corrupt-"
+
+// ErrCorruptStorage is matched by errors returned when the tasks file cannot be decoded
+var ErrCorruptStorage = errors.New("tasks file is corrupt")
+
+// CorruptFileError reports a tasks file that could not be decoded and was
+// moved out of the way instead of being overwritten.
+type CorruptFileError struct {
+	Path           string // Original location of the tasks file
+	QuarantinePath string // Where the damaged file was moved
+	Err            error  // Decoding error
+}
+
+func (e *CorruptFileError) Error() string {
+	return fmt.Sprintf("tasks file %s is corrupt (%v); it was moved to %s. Run 'task-tracker repair' to recover its tasks",
+		e.Path, e.Err, e.QuarantinePath)
+}
+
+func (e *CorruptFileError) Unwrap() error {
+	return ErrCorruptStorage
+}
+
+// quarantineMarker is kept next to a tasks file that was quarantined, so that
+// later runs keep refusing to start from an empty file until it is repaired
+type quarantineMarker struct {
+	QuarantinePath string `json:"quarantine"`
+	Error          string `json:"error"`
+}
+
+func markerPath(path string) string {
+	return path + ".quarantined"
+}
+
+// writeQuarantineMarker records that the tasks file at path was moved to
+// quarantined because of err
+func writeQuarantineMarker(path, quarantined string, err error) error {
+	data, merr := json.Marshal(quarantineMarker{QuarantinePath: quarantined, Error: err.Error()})
+	if merr != nil {
+		return merr
+	}
+	if err := os.WriteFile(markerPath(path), data, 0644); err != nil {
+		return fmt.Errorf("recording the quarantined file: %w", err)
+	}
+	return nil
+}
+
+// unresolvedQuarantine returns the error of a quarantine of the tasks file at
+// path not resolved yet, nil when there is none
+func unresolvedQuarantine(path string) error {
+	data, err := os.ReadFile(markerPath(path))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("checking for a quarantined tasks file: %w", err)
+	}
+
+	var m quarantineMarker
+	if err := json.Unmarshal(data, &m); err != nil {
+		m.Error = "unreadable quarantine record"
+	}
+	return &CorruptFileError{Path: path, QuarantinePath: m.QuarantinePath, Err: errors.New(m.Error)}
+}
+
+// ResolveQuarantine lets the tasks file at path be used again after it was
+// quarantined as corrupt. Until then opening it fails with a
+// *CorruptFileError, unless storage.recoverCorrupt is set.
+func ResolveQuarantine(path string) error {
+	if err := os.Remove(markerPath(path)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("clearing the quarantine of %s: %w", path, err)
+	}
+	return nil
+}
+
+// quarantineFile moves path into dir under a timestamped name and returns the new location
+func quarantineFile(path, dir string) (string, error) {
+	if dir == "" {
+		dir = filepath.Dir(path)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating quarantine directory: %w", err)
+	}
+
+	stamp := time.Now().UTC().Format("20060102T150405.000000000Z")
+	target := filepath.Join(dir, filepath.Base(path)+quarantineSuffix+stamp)
+
+	if err := os.Rename(path, target); err != nil {
+		// Rename fails across devices; fall back to copy and remove
+		if err := copyFile(path, target); err != nil {
+			return "", err
+		}
+		if err := os.Remove(path); err != nil {
+			return "", fmt.Errorf("removing corrupt file: %w", err)
+		}
+	}
+
+	return target, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// QuarantinedFiles returns the quarantined copies of the tasks file at path
+// found in dir, newest first.
+func QuarantinedFiles(path, dir string) ([]string, error) {
+	if dir == "" {
+		dir = filepath.Dir(path)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, filepath.Base(path)+quarantineSuffix+"*"))
+	if err != nil {
+		return nil, err
+	}
+
+	// The timestamp format sorts lexically
+	sort.Sort(sort.Reverse(sort.StringSlice(matches)))
+	return matches, nil
+}
+
+// SalvageTasks extracts every task object that can be decoded on its own from
+// damaged JSON. It returns the recovered tasks and the number of objects that
+// had to be skipped. Tasks that lost their status get the initial status of
+// workflow w.
+func SalvageTasks(data []byte, w *Workflow) ([]Task, int) {
+	var (
+		tasks   []Task
+		skipped int
+		seen    = map[string]bool{}
+	)
+
+	for _, raw := range topLevelObjects(data) {
+		var t Task
+		if err := json.Unmarshal(raw, &t); err != nil || strings.TrimSpace(t.ID) == "" || seen[t.ID] {
+			skipped++
+			continue
+		}
+		seen[t.ID] = true
+
+		// Fill in fields that a damaged object may have lost
+		if t.Status == "" {
+			t.Status = w.Initial()
+		}
+		if t.CreatedAt.IsZero() {
+			t.CreatedAt = time.Now()
+		}
+		if t.UpdatedAt.IsZero() {
+			t.UpdatedAt = t.CreatedAt
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks, skipped
+}
+
+// topLevelObjects returns the outermost {...} spans of data, honoring JSON
+// strings so braces inside titles or descriptions are not counted. An object
+// left open at the end of the input (a truncated write) is dropped.
+func topLevelObjects(data []byte) [][]byte {
+	var (
+		objects  [][]byte
+		depth    int
+		start    int
+		inString bool
+		escaped  bool
+	)
+
+	for i, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case '}':
+			if depth == 0 {
+				continue
+			}
+			depth--
+			if depth == 0 {
+				objects = append(objects, data[start:i+1])
+			}
+		}
+	}
+
+	return objects
+}
+
+// ImportTasks adds the given tasks in a single save, skipping tasks whose ID
+// already exists. Parent and dependency links that point to a missing task or
+// would create a cycle are dropped. It returns the number of tasks added.
+func (ts *TaskStorage) ImportTasks(tasks []Task) (int, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	added := 0
+	err := ts.transaction(OpImport, func(tx Store) error {
+		added = 0
+		var imported []Task
+		for _, t := range tasks {
+			if _, err := tx.Get(t.ID); err == nil {
+				continue
+			} else if !errors.Is(err, ErrTaskNotFound) {
+				return err
+			}
+			// Links are restored below, once every imported task exists
+			bare := t
+			bare.ParentID = ""
+			bare.DependsOn = nil
+			if err := tx.Put(bare); err != nil {
+				return err
+			}
+			imported = append(imported, t)
+			added++
+		}
+
+		for _, t := range imported {
+			if err := importLinks(tx, t); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to import tasks: %w", err)
+	}
+
+	return added, nil
+}
+
+// importLinks restores the parent and dependencies of t that are valid against
+// the tasks already in tx. Links are added one at a time, so a cycle among the
+// imported tasks is broken at its last link.
+func importLinks(tx Store, t Task) error {
+	linked := t
+	linked.ParentID = ""
+	linked.DependsOn = nil
+
+	if t.ParentID != "" {
+		candidate := linked
+		candidate.ParentID = t.ParentID
+		err := validateParent(tx, candidate)
+		switch {
+		case err == nil:
+			linked.ParentID = t.ParentID
+		case errors.Is(err, ErrValidation):
+			logger.Info("Dropped parent of imported task", zap.String("id", t.ID), zap.Error(err))
+		default:
+			return err
+		}
+	}
+
+	for _, dep := range t.DependsOn {
+		if slices.Contains(linked.DependsOn, dep) {
+			continue
+		}
+		err := validateDependency(tx, t.ID, dep)
+		switch {
+		case err == nil:
+			linked.DependsOn = append(linked.DependsOn, dep)
+		case errors.Is(err, ErrValidation):
+			logger.Info("Dropped dependency of imported task", zap.String("id", t.ID), zap.Error(err))
+		default:
+			return err
+		}
+	}
+
+	if linked.ParentID == "" && len(linked.DependsOn) == 0 {
+		return nil
+	}
+	return tx.Put(linked)
+}
@@ -0,0 +1,162 @@
+package task
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestSalvageTasks(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		data        string
+		expectedIDs []string
+		skipped     int
+	}{
+		{
+			name:        "Stray comma",
+			data:        `[{"id":"a1","title":"One","status":"TODO"},,{"id":"b2","title":"Two","status":"DONE"}]`,
+			expectedIDs: []string{"a1", "b2"},
+		},
+		{
+			name:        "Truncated write",
+			data:        `[{"id":"a1","title":"One"},{"id":"b2","title":"Tw`,
+			expectedIDs: []string{"a1"},
+		},
+		{
+			name:        "Braces and quotes inside strings",
+			data:        `[{"id":"a1","title":"fix {parser}","description":"say \"}\" twice"}, oops {"id":"b2"}]`,
+			expectedIDs: []string{"a1", "b2"},
+		},
+		{
+			name:        "Undecodable and duplicate objects",
+			data:        `[{"id":"a1"},{"id":42},{"title":"no id"},{"id":"a1"}]`,
+			expectedIDs: []string{"a1"},
+			skipped:     3,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			tasks, skipped := SalvageTasks([]byte(scenario.data), DefaultWorkflow)
+
+			if skipped != scenario.skipped {
+				t.Errorf("Expected %d skipped objects, got %d", scenario.skipped, skipped)
+			}
+			if len(tasks) != len(scenario.expectedIDs) {
+				t.Fatalf("Expected %d tasks, got %d", len(scenario.expectedIDs), len(tasks))
+			}
+			for i, id := range scenario.expectedIDs {
+				if tasks[i].ID != id {
+					t.Errorf("Expected task %d to be %s, got %s", i, id, tasks[i].ID)
+				}
+				if tasks[i].Status == "" || tasks[i].CreatedAt.IsZero() {
+					t.Errorf("Expected salvaged task %s to have status and timestamps filled in", id)
+				}
+			}
+		})
+	}
+}
+
+func TestCorruptFileIsQuarantined(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+
+	damaged := []byte(`[{"id":"a1","title":"Keep me","status":"TODO"},]`)
+	if err := os.WriteFile(cfg.Storage.FilePath, damaged, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewTaskStorageWithConfig(&cfg)
+	var corrupt *CorruptFileError
+	if !errors.As(err, &corrupt) || !errors.Is(err, ErrCorruptStorage) {
+		t.Fatalf("Expected CorruptFileError, got %v", err)
+	}
+
+	files, err := QuarantinedFiles(cfg.Storage.FilePath, cfg.Storage.BackupDir)
+	if err != nil || len(files) != 1 || files[0] != corrupt.QuarantinePath {
+		t.Fatalf("Expected the quarantined file to be listed, got %v (%v)", files, err)
+	}
+
+	data, err := os.ReadFile(corrupt.QuarantinePath)
+	if err != nil || string(data) != string(damaged) {
+		t.Fatalf("Expected quarantined file to keep the damaged content, got %q (%v)", data, err)
+	}
+
+	// The next runs keep refusing until the quarantine is resolved, then
+	// start from a fresh file that repair merges the salvaged tasks into
+	if _, err := NewTaskStorageWithConfig(&cfg); !errors.Is(err, ErrCorruptStorage) {
+		t.Fatalf("Expected the next run to refuse the quarantined file, got %v", err)
+	}
+	if err := ResolveQuarantine(cfg.Storage.FilePath); err != nil {
+		t.Fatal(err)
+	}
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error reopening storage: %v", err)
+	}
+
+	salvaged, _ := SalvageTasks(data, DefaultWorkflow)
+	added, err := ts.ImportTasks(salvaged)
+	if err != nil || added != 1 {
+		t.Fatalf("Expected 1 imported task, got %d (%v)", added, err)
+	}
+	if added, _ := ts.ImportTasks(salvaged); added != 0 {
+		t.Errorf("Expected existing tasks to be skipped, got %d added", added)
+	}
+	if _, err := ts.GetTask("a1"); err != nil {
+		t.Errorf("Expected recovered task to be stored: %v", err)
+	}
+}
+
+func TestCorruptFileRecoverCorrupt(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	cfg.Storage.RecoverCorrupt = true
+
+	if err := os.WriteFile(cfg.Storage.FilePath, []byte(`{invalid json}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatalf("Expected storage to continue with recoverCorrupt, got %v", err)
+	}
+	if len(ts.ListTasks()) != 0 {
+		t.Errorf("Expected empty task list")
+	}
+
+	files, _ := QuarantinedFiles(cfg.Storage.FilePath, cfg.Storage.BackupDir)
+	if len(files) != 1 {
+		t.Errorf("Expected the corrupt file to be quarantined, found %d files", len(files))
+	}
+}
+
+func TestImportTasksDropsInvalidLinks(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	added, err := ts.ImportTasks([]Task{
+		{ID: "a", Title: "A", Status: StatusTodo, ParentID: "b", DependsOn: []string{"b", "missing"}},
+		{ID: "b", Title: "B", Status: StatusTodo, ParentID: "a", DependsOn: []string{"a"}},
+		{ID: "c", Title: "C", Status: StatusTodo, ParentID: "gone", DependsOn: []string{"c"}},
+	})
+	if err != nil || added != 3 {
+		t.Fatalf("Expected 3 imported tasks, got %d (%v)", added, err)
+	}
+
+	a, _ := ts.GetTask("a")
+	if a.ParentID != "b" || !reflect.DeepEqual(a.DependsOn, []string{"b"}) {
+		t.Errorf("Expected a to keep its valid links, got parent %q and deps %v", a.ParentID, a.DependsOn)
+	}
+	b, _ := ts.GetTask("b")
+	if b.ParentID != "" || len(b.DependsOn) != 0 {
+		t.Errorf("Expected the links closing the cycles to be dropped, got parent %q and deps %v", b.ParentID, b.DependsOn)
+	}
+	c, _ := ts.GetTask("c")
+	if c.ParentID != "" || len(c.DependsOn) != 0 {
+		t.Errorf("Expected links to missing tasks and to itself to be dropped, got parent %q and deps %v", c.ParentID, c.DependsOn)
+	}
+}
@@ -0,0 +1,220 @@
+package task
+
+import (
+	"sort"
+	"time"
+)
+
+// Period is the length of the intervals a report is split into
+type Period string
+
+const (
+	PeriodDay  Period = "day"
+	PeriodWeek Period = "week" // Weeks start on Monday
+)
+
+// untaggedLabel groups the time spent on tasks without tags
+const untaggedLabel = "(untagged)"
+
+// Report summarizes the throughput of tasks and the time spent on them
+// between Since and Until, both included
+type Report struct {
+	Period     Period
+	Since      time.Time
+	Until      time.Time
+	Periods    []PeriodSummary
+	Total      PeriodSummary
+	TimePerTag []TagTime
+}
+
+// PeriodSummary counts the tasks created and completed in a period, with the
+// average lead and cycle time of the completed ones
+type PeriodSummary struct {
+	Start     time.Time
+	Created   int
+	Completed int
+	// LeadTime is the average time from creation to completion
+	LeadTime time.Duration
+	// CycleTime is the average time from the start of the work to completion,
+	// over the completed tasks that went through IN_PROGRESS or were timed
+	CycleTime time.Duration
+
+	leadTotal, cycleTotal time.Duration
+	cycles                int
+}
+
+// TagTime is the work logged on tasks carrying a tag
+type TagTime struct {
+	Tag   string
+	Spent time.Duration
+}
+
+// PeriodStart returns the start of the period containing t
+func PeriodStart(p Period, t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if p == PeriodWeek {
+		// time.Weekday counts from Sunday; weeks start on Monday
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	}
+	return day
+}
+
+func (p Period) next(start time.Time) time.Time {
+	if p == PeriodWeek {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// CompletedAt returns when t last reached a done status of workflow w, or
+// false when t is not done. Tasks finished before history was recorded fall
+// back to their last update.
+func (t Task) CompletedAt(w *Workflow) (time.Time, bool) {
+	if !w.IsDone(t.Status) {
+		return time.Time{}, false
+	}
+	for i := len(t.History) - 1; i >= 0; i-- {
+		c := t.History[i]
+		if c.Field == "status" && w.IsDone(Status(c.New)) && !w.IsDone(Status(c.Old)) {
+			return c.At, true
+		}
+	}
+	return t.UpdatedAt, true
+}
+
+// StartedAt returns when the work on t started: the first move to the started
+// status of workflow w or the first timer, whichever came first, but not
+// before t was created, as logged work may be backdated. It is false for tasks
+// never started.
+func (t Task) StartedAt(w *Workflow) (time.Time, bool) {
+	var started time.Time
+	for _, c := range t.History {
+		if c.Field == "status" && Status(c.New) == w.Started() {
+			started = c.At
+			break
+		}
+	}
+	for _, e := range t.Work {
+		if started.IsZero() || e.Start.Before(started) {
+			started = e.Start
+		}
+	}
+	if started.IsZero() {
+		return started, false
+	}
+	if started.Before(t.CreatedAt) {
+		started = t.CreatedAt
+	}
+	return started, true
+}
+
+// BuildReport summarizes tasks between since and until, split into periods.
+// Completions follow the done statuses of workflow w and now bounds the
+// running timers.
+func BuildReport(tasks []Task, w *Workflow, period Period, since, until, now time.Time) Report {
+	r := Report{Period: period, Since: since, Until: until}
+	for start := PeriodStart(period, since); !start.After(until); start = period.next(start) {
+		r.Periods = append(r.Periods, PeriodSummary{Start: start})
+	}
+	r.Total.Start = since
+
+	// summary returns the period containing at, or nil when out of range
+	summary := func(at time.Time) *PeriodSummary {
+		if at.Before(since) || at.After(until) {
+			return nil
+		}
+		start := PeriodStart(period, at)
+		i := sort.Search(len(r.Periods), func(i int) bool { return !r.Periods[i].Start.Before(start) })
+		if i == len(r.Periods) {
+			return nil
+		}
+		return &r.Periods[i]
+	}
+
+	perTag := make(map[string]time.Duration)
+	for _, t := range tasks {
+		if p := summary(t.CreatedAt); p != nil {
+			p.Created++
+			r.Total.Created++
+		}
+		if done, ok := t.CompletedAt(w); ok {
+			if p := summary(done); p != nil {
+				for _, s := range []*PeriodSummary{p, &r.Total} {
+					s.Completed++
+					s.leadTotal += done.Sub(t.CreatedAt)
+					if started, ok := t.StartedAt(w); ok && !started.After(done) {
+						s.cycleTotal += done.Sub(started)
+						s.cycles++
+					}
+				}
+			}
+		}
+
+		spent := workBetween(t.Work, since, until, now)
+		if spent == 0 {
+			continue
+		}
+		if len(t.Tags) == 0 {
+			perTag[untaggedLabel] += spent
+		}
+		for _, tag := range t.Tags {
+			perTag[tag] += spent
+		}
+	}
+
+	for i := range r.Periods {
+		r.Periods[i].average()
+	}
+	r.Total.average()
+
+	for tag, spent := range perTag {
+		r.TimePerTag = append(r.TimePerTag, TagTime{Tag: tag, Spent: spent})
+	}
+	sort.Slice(r.TimePerTag, func(i, j int) bool {
+		if r.TimePerTag[i].Spent != r.TimePerTag[j].Spent {
+			return r.TimePerTag[i].Spent > r.TimePerTag[j].Spent
+		}
+		return r.TimePerTag[i].Tag < r.TimePerTag[j].Tag
+	})
+	return r
+}
+
+func (s *PeriodSummary) average() {
+	if s.Completed > 0 {
+		s.LeadTime = s.leadTotal / time.Duration(s.Completed)
+	}
+	if s.cycles > 0 {
+		s.CycleTime = s.cycleTotal / time.Duration(s.cycles)
+	}
+}
+
+// workBetween returns the part of the work entries falling between since and
+// until, counting running timers up to now
+func workBetween(work []WorkEntry, since, until, now time.Time) time.Duration {
+	var total time.Duration
+	for _, e := range work {
+		end := now
+		if e.End != nil {
+			end = *e.End
+		}
+		start := e.Start
+		if start.Before(since) {
+			start = since
+		}
+		if end.After(until) {
+			end = until
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return total
+}
+
+// Report summarizes the stored tasks, see BuildReport
+func (ts *TaskStorage) Report(period Period, since, until time.Time) Report {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	return BuildReport(ts.tasks, ts.workflow, period, since, until, time.Now())
+}
@@ -0,0 +1,106 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/config"
+)
+
+func TestPeriodStart(t *testing.T) {
+	// 2025-03-13 is a Thursday
+	at := time.Date(2025, 3, 13, 15, 4, 5, 0, time.UTC)
+	if got := PeriodStart(PeriodDay, at); !got.Equal(time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the day to start at midnight, got %v", got)
+	}
+	if got := PeriodStart(PeriodWeek, at); !got.Equal(time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the week to start on Monday, got %v", got)
+	}
+	sunday := time.Date(2025, 3, 16, 23, 0, 0, 0, time.UTC)
+	if got := PeriodStart(PeriodWeek, sunday); !got.Equal(time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected Sunday to belong to the week started on Monday, got %v", got)
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	day := func(d, h int) time.Time { return time.Date(2025, 3, d, h, 0, 0, 0, time.UTC) }
+	end := func(t time.Time) *time.Time { return &t }
+	status := func(from, to Status, at time.Time) Change {
+		return Change{FieldChange: FieldChange{Field: "status", Old: string(from), New: string(to)}, At: at}
+	}
+
+	tasks := []Task{
+		{
+			ID: "1", Status: StatusDone, Tags: []string{"backend"}, CreatedAt: day(10, 9),
+			History: []Change{status(StatusTodo, StatusInProgress, day(11, 9)), status(StatusInProgress, StatusDone, day(12, 9))},
+			Work:    []WorkEntry{{Start: day(11, 9), End: end(day(11, 11))}},
+		},
+		{
+			// Done without going through IN_PROGRESS: the cycle starts with the timer
+			ID: "2", Status: StatusDone, CreatedAt: day(11, 9),
+			History: []Change{status(StatusTodo, StatusDone, day(11, 21))},
+			Work:    []WorkEntry{{Start: day(11, 20), End: end(day(11, 21))}},
+		},
+		{
+			ID: "3", Status: StatusInProgress, Tags: []string{"backend", "api"}, CreatedAt: day(12, 9),
+			// Started before the report: only the part in range counts
+			Work: []WorkEntry{{Start: day(9, 23), End: end(day(10, 1))}},
+		},
+		// Created out of range
+		{ID: "4", Status: StatusTodo, CreatedAt: day(1, 9)},
+	}
+
+	r := BuildReport(tasks, DefaultWorkflow, PeriodDay, day(10, 0), day(12, 23), day(12, 23))
+	if len(r.Periods) != 3 {
+		t.Fatalf("Expected 3 days, got %d", len(r.Periods))
+	}
+	if p := r.Periods[1]; p.Created != 1 || p.Completed != 1 || p.LeadTime != 12*time.Hour || p.CycleTime != time.Hour {
+		t.Errorf("Unexpected summary of the second day: %+v", p)
+	}
+	if p := r.Periods[2]; p.Completed != 1 || p.LeadTime != 48*time.Hour || p.CycleTime != 24*time.Hour {
+		t.Errorf("Unexpected summary of the third day: %+v", p)
+	}
+	if r.Total.Created != 3 || r.Total.Completed != 2 || r.Total.LeadTime != 30*time.Hour || r.Total.CycleTime != 12*time.Hour+30*time.Minute {
+		t.Errorf("Unexpected total: %+v", r.Total)
+	}
+
+	want := []TagTime{{"backend", 3 * time.Hour}, {"(untagged)", time.Hour}, {"api", time.Hour}}
+	if len(r.TimePerTag) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, r.TimePerTag)
+	}
+	for i := range want {
+		if r.TimePerTag[i] != want[i] {
+			t.Errorf("Expected %v at %d, got %v", want[i], i, r.TimePerTag[i])
+		}
+	}
+
+	weekly := BuildReport(tasks, DefaultWorkflow, PeriodWeek, day(1, 0), day(12, 23), day(12, 23))
+	if len(weekly.Periods) != 3 || weekly.Periods[0].Created != 1 || weekly.Periods[2].Created != 3 {
+		t.Errorf("Unexpected weekly report: %+v", weekly.Periods)
+	}
+}
+
+func TestTask_StartedAtWorkflow(t *testing.T) {
+	created := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	doing := created.Add(2 * time.Hour)
+	task := Task{
+		ID: "1", Status: "SHIPPED", CreatedAt: created,
+		History: []Change{
+			{FieldChange: FieldChange{Field: "status", Old: "BACKLOG", New: "DOING"}, At: doing},
+			{FieldChange: FieldChange{Field: "status", Old: "DOING", New: "SHIPPED"}, At: doing.Add(time.Hour)},
+		},
+	}
+
+	kanban, err := NewWorkflow(config.WorkflowConfig{Statuses: []config.StatusConfig{
+		{Name: "BACKLOG"}, {Name: "DOING"}, {Name: "SHIPPED", Done: true},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if started, ok := task.StartedAt(kanban); !ok || !started.Equal(doing) {
+		t.Errorf("Expected the move to DOING to start the work, got %v, %v", started, ok)
+	}
+	if _, ok := task.StartedAt(DefaultWorkflow); ok {
+		t.Errorf("Expected no start without a move to IN_PROGRESS or a timer")
+	}
+}
@@ -0,0 +1,272 @@
+package task
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Relevance weights of a search hit. A term matching a whole word counts
+// more than one matching only its beginning, and title hits count more than
+// description hits.
+const (
+	titleWeight       = 3.0
+	descriptionWeight = 1.0
+	prefixFactor      = 0.5
+)
+
+// SearchResult is a task matching a search with its relevance
+type SearchResult struct {
+	Task  Task
+	Score float64
+	terms []string // Folded query terms, used for highlighting
+}
+
+// searchIndex is an inverted index from folded words to the tasks containing them
+type searchIndex struct {
+	postings map[string][]posting
+	words    []string // Sorted keys of postings, for prefix lookups
+}
+
+// posting records how often a word occurs in a task
+type posting struct {
+	task        int // Index of the task in the snapshot the index was built from
+	title, desc int // Occurrences in the title and the description
+}
+
+// word is a token of a text with its byte offsets in the original text
+type word struct {
+	folded     string
+	start, end int
+}
+
+// foldWord lower-cases s and strips diacritics, so "Café" matches "cafe"
+func foldWord(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// tokenize splits text into words made of letters and digits
+func tokenize(text string) []word {
+	var words []word
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			words = append(words, word{folded: foldWord(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{folded: foldWord(text[start:]), start: start, end: len(text)})
+	}
+	return words
+}
+
+// searchTerms returns the distinct folded terms of a search query
+func searchTerms(query string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, w := range tokenize(query) {
+		if !seen[w.folded] {
+			seen[w.folded] = true
+			terms = append(terms, w.folded)
+		}
+	}
+	return terms
+}
+
+func buildSearchIndex(tasks []Task) *searchIndex {
+	idx := &searchIndex{postings: make(map[string][]posting)}
+
+	for i, task := range tasks {
+		counts := make(map[string]*posting)
+		count := func(text string, title bool) {
+			for _, w := range tokenize(text) {
+				p := counts[w.folded]
+				if p == nil {
+					p = &posting{task: i}
+					counts[w.folded] = p
+				}
+				if title {
+					p.title++
+				} else {
+					p.desc++
+				}
+			}
+		}
+		count(task.Title, true)
+		count(task.Description, false)
+
+		for w, p := range counts {
+			idx.postings[w] = append(idx.postings[w], *p)
+		}
+	}
+
+	for w := range idx.postings {
+		idx.words = append(idx.words, w)
+	}
+	sort.Strings(idx.words)
+	return idx
+}
+
+// score returns the relevance of every task for term, counting words that
+// start with term as weaker matches
+func (idx *searchIndex) score(term string) map[int]float64 {
+	scores := make(map[int]float64)
+	for i := sort.SearchStrings(idx.words, term); i < len(idx.words) && strings.HasPrefix(idx.words[i], term); i++ {
+		w := idx.words[i]
+		factor := 1.0
+		if w != term {
+			factor = prefixFactor
+		}
+		for _, p := range idx.postings[w] {
+			scores[p.task] += factor * (titleWeight*float64(p.title) + descriptionWeight*float64(p.desc))
+		}
+	}
+	return scores
+}
+
+// searchIndex returns the index of the current snapshot, building it on first
+// use so that commands which never search don't pay for it. Callers must hold
+// ts.mu for reading.
+func (ts *TaskStorage) searchIndex() *searchIndex {
+	ts.indexMu.Lock()
+	defer ts.indexMu.Unlock()
+
+	if ts.index == nil {
+		ts.index = buildSearchIndex(ts.tasks)
+	}
+	return ts.index
+}
+
+// Search returns the tasks whose title or description contain every term of
+// query, either as a whole word or as the beginning of one. Matching ignores
+// case and diacritics. Results are ordered by relevance, most relevant first.
+func (ts *TaskStorage) Search(query string) []SearchResult {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	index := ts.searchIndex()
+	var total map[int]float64
+	for _, term := range terms {
+		scores := index.score(term)
+		if total == nil {
+			total = scores
+			continue
+		}
+		for i := range total {
+			if s, ok := scores[i]; ok {
+				total[i] += s
+			} else {
+				delete(total, i)
+			}
+		}
+	}
+
+	results := make([]SearchResult, 0, len(total))
+	for i, score := range total {
+		results = append(results, SearchResult{Task: ts.tasks[i], Score: score, terms: terms})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Task.UpdatedAt.After(results[j].Task.UpdatedAt)
+	})
+	return results
+}
+
+// Title returns the task title with the matched words passed through mark
+func (r SearchResult) Title(mark func(string) string) string {
+	return r.highlight(r.Task.Title, mark)
+}
+
+// Snippet returns an excerpt of about width runes of the description around
+// the first matched word, with matched words passed through mark
+func (r SearchResult) Snippet(width int, mark func(string) string) string {
+	text := r.Task.Description
+	start, end := 0, len(text)
+
+	match := 0
+	for _, w := range tokenize(text) {
+		if r.matches(w.folded) {
+			match = w.start
+			break
+		}
+	}
+
+	// Center the excerpt on the first match, moving to word boundaries
+	runes := []rune(text)
+	if len(runes) > width {
+		before := width / 3
+		startRune := len([]rune(text[:match])) - before
+		if startRune < 0 {
+			startRune = 0
+		}
+		if startRune+width > len(runes) {
+			startRune = len(runes) - width
+		}
+		start = len(string(runes[:startRune]))
+		end = len(string(runes[:startRune+width]))
+		if start > 0 {
+			if i := strings.IndexFunc(text[start:end], unicode.IsSpace); i >= 0 {
+				start += i + 1
+			}
+		}
+		if end < len(text) {
+			if i := strings.LastIndexFunc(text[start:end], unicode.IsSpace); i > 0 {
+				end = start + i
+			}
+		}
+	}
+
+	snippet := r.highlight(text[start:end], mark)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+func (r SearchResult) matches(folded string) bool {
+	for _, term := range r.terms {
+		if strings.HasPrefix(folded, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r SearchResult) highlight(text string, mark func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, w := range tokenize(text) {
+		if !r.matches(w.folded) {
+			continue
+		}
+		b.WriteString(text[last:w.start])
+		b.WriteString(mark(text[w.start:w.end]))
+		last = w.end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
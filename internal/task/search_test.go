@@ -0,0 +1,92 @@
+package task
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	words := tokenize("Café-menu, ÉTÉ 2026!")
+
+	expected := []string{"cafe", "menu", "ete", "2026"}
+	if len(words) != len(expected) {
+		t.Fatalf("Expected %d words, got %+v", len(expected), words)
+	}
+	for i, w := range words {
+		if w.folded != expected[i] {
+			t.Errorf("Expected word %d to be %q, got %q", i, expected[i], w.folded)
+		}
+	}
+}
+
+func TestTaskStorage_Search(t *testing.T) {
+	ts, _ := setupTestStorage(t)
+	if ts.index != nil {
+		t.Errorf("Expected the index to be built on the first search only")
+	}
+
+	for _, task := range [][2]string{
+		{"Write docs", "Explain the deployment of the API"},
+		{"Deploy API", "Roll out to production"},
+		{"Café menu", "Print the new menu"},
+	} {
+		if _, err := ts.AddTask(task[0], task[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scenarios := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{"Title hits rank first", "deploy", []string{"Deploy API", "Write docs"}},
+		{"Prefix match", "depl", []string{"Deploy API", "Write docs"}},
+		{"Diacritics and case are folded", "CAFE", []string{"Café menu"}},
+		{"All terms must match", "api production", []string{"Deploy API"}},
+		{"No match", "kubernetes", nil},
+		{"No terms", "!!", nil},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			results := ts.Search(scenario.query)
+
+			var titles []string
+			for _, r := range results {
+				titles = append(titles, r.Task.Title)
+			}
+			if strings.Join(titles, "|") != strings.Join(scenario.expected, "|") {
+				t.Errorf("Expected %v, got %v", scenario.expected, titles)
+			}
+		})
+	}
+
+	// The index is dropped with the snapshot and rebuilt by the next search
+	if _, err := ts.AddTask("Kubernetes upgrade", ""); err != nil {
+		t.Fatal(err)
+	}
+	if ts.index != nil {
+		t.Errorf("Expected a change to drop the index until the next search")
+	}
+	if results := ts.Search("kubernetes"); len(results) != 1 {
+		t.Errorf("Expected the new task to be found, got %d results", len(results))
+	}
+}
+
+func TestSearchResult_Highlight(t *testing.T) {
+	result := SearchResult{
+		Task:  Task{Title: "Deploy API", Description: strings.Repeat("filler ", 20) + "the deployment runbook"},
+		terms: []string{"depl"},
+	}
+	mark := func(s string) string { return "[" + s + "]" }
+
+	if title := result.Title(mark); title != "[Deploy] API" {
+		t.Errorf("Unexpected title %q", title)
+	}
+
+	snippet := result.Snippet(40, mark)
+	if !strings.HasPrefix(snippet, "…") || !strings.Contains(snippet, "[deployment]") {
+		t.Errorf("Expected a trimmed snippet around the match, got %q", snippet)
+	}
+}
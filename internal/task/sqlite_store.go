@@ -0,0 +1,192 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS tasks (
+	seq  INTEGER PRIMARY KEY AUTOINCREMENT,
+	id   TEXT NOT NULL UNIQUE,
+	data TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value INTEGER NOT NULL
+)`
+
+// sequenceKey is the meta row holding the highest sequential ID issued
+const sequenceKey = "sequence"
+
+// sqliteStore keeps one row per task, so a change only rewrites the affected rows.
+// Tasks are stored as JSON documents to keep the schema independent of Task fields.
+type sqliteStore struct {
+	db *sql.DB
+}
+
+// sqlRunner is implemented by both *sql.DB and *sql.Tx
+type sqlRunner interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func newSQLiteStore(path string) (*sqliteStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("sqlite storage requires a file path")
+	}
+
+	dsn := "file:" + path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening sqlite database: %w", err)
+	}
+	// A single connection serializes writers inside this process
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error initializing sqlite database: %w", err)
+	}
+
+	return &sqliteStore{db: db}, nil
+}
+
+func (s *sqliteStore) Get(id string) (Task, error) { return sqlGet(s.db, id) }
+func (s *sqliteStore) List() ([]Task, error)       { return sqlList(s.db) }
+func (s *sqliteStore) Put(t Task) error            { return sqlPut(s.db, t) }
+func (s *sqliteStore) Delete(id string) error      { return sqlDelete(s.db, id) }
+func (s *sqliteStore) Sequence() (int, error)      { return sqlSequence(s.db) }
+func (s *sqliteStore) SetSequence(n int) error     { return sqlSetSequence(s.db, n) }
+func (s *sqliteStore) Close() error                { return s.db.Close() }
+
+func (s *sqliteStore) Transaction(fn func(tx Store) error) error {
+	tx, err := s.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	if err := fn(&sqliteTx{tx: tx}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+// sqliteTx is the view handed to sqliteStore transactions
+type sqliteTx struct {
+	tx *sql.Tx
+}
+
+func (t *sqliteTx) Get(id string) (Task, error) { return sqlGet(t.tx, id) }
+func (t *sqliteTx) List() ([]Task, error)       { return sqlList(t.tx) }
+func (t *sqliteTx) Put(task Task) error         { return sqlPut(t.tx, task) }
+func (t *sqliteTx) Delete(id string) error      { return sqlDelete(t.tx, id) }
+func (t *sqliteTx) Sequence() (int, error)      { return sqlSequence(t.tx) }
+func (t *sqliteTx) SetSequence(n int) error     { return sqlSetSequence(t.tx, n) }
+func (t *sqliteTx) Close() error                { return nil }
+
+// Transaction runs fn within the enclosing transaction
+func (t *sqliteTx) Transaction(fn func(tx Store) error) error {
+	return fn(t)
+}
+
+func sqlGet(r sqlRunner, id string) (Task, error) {
+	var data string
+	err := r.QueryRowContext(context.Background(), `SELECT data FROM tasks WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Task{}, ErrTaskNotFound
+	}
+	if err != nil {
+		return Task{}, fmt.Errorf("error reading task %s: %w", id, err)
+	}
+
+	var t Task
+	if err := json.Unmarshal([]byte(data), &t); err != nil {
+		return Task{}, fmt.Errorf("error deserializing task %s: %w", id, err)
+	}
+	t.applyDefaults()
+	return t, nil
+}
+
+func sqlList(r sqlRunner) ([]Task, error) {
+	rows, err := r.QueryContext(context.Background(), `SELECT data FROM tasks ORDER BY seq`)
+	if err != nil {
+		return nil, fmt.Errorf("error listing tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []Task{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("error reading task row: %w", err)
+		}
+		var t Task
+		if err := json.Unmarshal([]byte(data), &t); err != nil {
+			return nil, fmt.Errorf("error deserializing task: %w", err)
+		}
+		t.applyDefaults()
+		tasks = append(tasks, t)
+	}
+
+	return tasks, rows.Err()
+}
+
+func sqlPut(r sqlRunner, t Task) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("error serializing task %s: %w", t.ID, err)
+	}
+
+	_, err = r.ExecContext(context.Background(),
+		`INSERT INTO tasks (id, data) VALUES (?, ?) ON CONFLICT(id) DO UPDATE SET data = excluded.data`,
+		t.ID, string(data))
+	if err != nil {
+		return fmt.Errorf("error saving task %s: %w", t.ID, err)
+	}
+	return nil
+}
+
+func sqlDelete(r sqlRunner, id string) error {
+	res, err := r.ExecContext(context.Background(), `DELETE FROM tasks WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting task %s: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrTaskNotFound
+	}
+	return nil
+}
+
+func sqlSequence(r sqlRunner) (int, error) {
+	var n int
+	err := r.QueryRowContext(context.Background(), `SELECT value FROM meta WHERE key = ?`, sequenceKey).Scan(&n)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error reading the ID sequence: %w", err)
+	}
+	return n, nil
+}
+
+func sqlSetSequence(r sqlRunner, n int) error {
+	_, err := r.ExecContext(context.Background(),
+		`INSERT INTO meta (key, value) VALUES (?, ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+		sequenceKey, n)
+	if err != nil {
+		return fmt.Errorf("error saving the ID sequence: %w", err)
+	}
+	return nil
+}
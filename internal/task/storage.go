@@ -2,76 +2,132 @@ package task
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
+	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/config"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/logger"
+	"go.uber.org/zap"
 )
 
 var ErrNoUpdatesProvided = errors.New("no updates provided")
 
 // Package task provides functionality for managing tasks in a task tracking system.
 type TaskStorage struct {
-	mu       sync.RWMutex // Protects concurrent access to tasks
-	tasks    []Task       // Slice of tasks in memory
-	filePath string       // Path to the JSON storage file
+	mu       sync.RWMutex  // Protects concurrent access to tasks
+	tasks    []Task        // Snapshot of the store, refreshed after every change
+	store    Store         // Backend persisting the tasks
+	journal  *Journal      // Log of operations for undo/redo
+	pending  []journaled   // Journal entries of the running transaction
+	index    *searchIndex  // Full-text index of tasks, built on the first search of a snapshot
+	indexMu  sync.Mutex    // Serializes building the index between readers of ts.mu
+	workflow *Workflow     // Statuses and transitions from cfg
+	cfg      config.Config // Settings used for validation and storage
 }
 
 // NewTaskStorage creates a new TaskStorage instance with the specified file path.
 // It loads existing tasks from the file if it exists, or creates a new file if it doesn't.
+// Backups are kept in the default backup directory next to the file.
 // Returns an error if the file operations fail.
-func NewTaskStorage(filepath string) (*TaskStorage, error) {
+func NewTaskStorage(path string) (*TaskStorage, error) {
+	cfg := config.DefaultConfig
+	cfg.Storage.FilePath = path
+	cfg.Storage.BackupDir = filepath.Join(filepath.Dir(path), config.DefaultConfig.Storage.BackupDir)
+
+	return NewTaskStorageWithConfig(&cfg)
+}
+
+// NewTaskStorageWithConfig creates a new TaskStorage using the backend selected by
+// cfg.Storage and validating tasks against cfg.Task.
+func NewTaskStorageWithConfig(cfg *config.Config) (*TaskStorage, error) {
+	store, err := OpenStore(cfg.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTaskStorageWithStore(store, cfg)
+}
+
+// NewTaskStorageWithStore creates a new TaskStorage on top of an already opened
+// store. Statuses are checked against the workflow of cfg, see Workflow.
+func NewTaskStorageWithStore(store Store, cfg *config.Config) (*TaskStorage, error) {
+	workflow, err := NewWorkflow(cfg.Task.Workflow)
+	if err != nil {
+		return nil, err
+	}
+
 	ts := &TaskStorage{
 		tasks:    []Task{},
-		filePath: filepath,
+		store:    store,
+		journal:  NewJournal(cfg.Storage.FilePath + ".journal"),
+		workflow: workflow,
+		cfg:      *cfg,
 	}
 
-	if err := ts.loadFromFile(); err != nil {
+	if err := ts.refresh(); err != nil {
 		return nil, err
 	}
 
 	return ts, nil
 }
 
-// AddTask creates a new task with the given title and description
-func (ts *TaskStorage) AddTask(title, description string) (*Task, error) {
+// Workflow returns the workflow the statuses of the tasks follow
+func (ts *TaskStorage) Workflow() *Workflow {
+	return ts.workflow
+}
+
+// Close releases the underlying store
+func (ts *TaskStorage) Close() error {
+	return ts.store.Close()
+}
+
+// AddTask creates a new task with the given title, description and options
+func (ts *TaskStorage) AddTask(title, description string, opts ...TaskOption) (*Task, error) {
 	ts.mu.Lock()
 	defer ts.mu.Unlock()
 
-	task, err := NewTask(title, description)
+	task, err := NewTaskWithConfig(title, description, ts.cfg.Task, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	ts.tasks = append(ts.tasks, *task)
-
-	if err := ts.saveToFile(); err != nil {
+	err = ts.transaction(OpAdd, func(tx Store) error {
+		if err := assignID(tx, task, ts.cfg.Task.IDStrategy); err != nil {
+			return err
+		}
+		if err := validateParent(tx, *task); err != nil {
+			return err
+		}
+		return tx.Put(*task)
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to save task: %w", err)
 	}
 
 	return task, nil
 }
 
+// ListTasks returns a copy of all tasks, safe for the caller to sort or modify
 func (ts *TaskStorage) ListTasks() []Task {
 	ts.mu.RLock()
 	defer ts.mu.RUnlock()
 
-	return ts.tasks
+	return append([]Task(nil), ts.tasks...)
 }
 
+// GetTask returns the task with exactly the given ID, see ResolveID for prefixes
 func (ts *TaskStorage) GetTask(id string) (Task, error) {
 	ts.mu.RLock()
 	defer ts.mu.RUnlock()
 
-	idx, task, err := ts.findTaskById(id)
+	idx, _, err := ts.findTaskById(id)
 	if err != nil {
 		return Task{}, err
 	}
 
-	fmt.Println("Task found:", task)
-
 	return ts.tasks[idx], nil
 }
 
@@ -82,10 +138,18 @@ func (ts *TaskStorage) findTaskById(id string) (int, Task, error) {
 		}
 	}
 
-	return -1, Task{}, fmt.Errorf("task not found")
+	return -1, Task{}, ErrTaskNotFound
 }
 
-func (ts *TaskStorage) UpdateTask(ctx context.Context, taskID string, updates map[string]interface{}) (*Task, error) {
+// UpdateTask applies patch to the task with the given ID and saves it.
+// The result is validated before saving; on any error the stored and
+// in-memory tasks are left unchanged. Status changes must be allowed by the
+// workflow unless patch.Force is set. A running timer is stopped when the
+// task reaches a done status, and a recurring task reaching it spawns its next
+// occurrence in the same operation, see Recurrence. Errors wrap ErrTaskNotFound,
+// ErrNoUpdatesProvided, ErrTransitionNotAllowed or a *ValidationError where
+// applicable.
+func (ts *TaskStorage) UpdateTask(ctx context.Context, taskID string, patch TaskPatch) (*Task, error) {
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -93,54 +157,63 @@ func (ts *TaskStorage) UpdateTask(ctx context.Context, taskID string, updates ma
 		ts.mu.Lock()
 		defer ts.mu.Unlock()
 
-		// Find the task by ID
-		idx, _, err := ts.findTaskById(taskID)
-		if err != nil {
-			return nil, err
-		}
+		var updated Task
+		err := ts.transaction(OpUpdate, func(tx Store) error {
+			// Find the task by ID
+			task, err := tx.Get(taskID)
+			if err != nil {
+				return err
+			}
 
-		// Apply updates
-		for field, value := range updates {
-			switch field {
-			case "title":
-				if title, ok := value.(string); ok {
-					if title == "" {
-						ts.tasks[idx].Title = "Untitled Task"
-					} else {
-						ts.tasks[idx].Title = title
-					}
-				}
-			case "description":
-				if desc, ok := value.(string); ok {
-					ts.tasks[idx].Description = desc
+			before := task.Status
+			task, err = patch.Apply(task, ts.cfg.Task)
+			if err != nil {
+				return err
+			}
+			if !patch.Force && !ts.workflow.CanTransition(before, task.Status) {
+				return fmt.Errorf("%w: %s -> %s", ErrTransitionNotAllowed, before, task.Status)
+			}
+			if patch.ParentID != nil {
+				if err := validateParent(tx, task); err != nil {
+					return err
 				}
-			case "status":
-				if statusStr, ok := value.(string); ok {
-					status, err := ValidateStatus(statusStr)
-					if err != nil {
-						return nil, err
-					}
-					ts.tasks[idx].Status = status
-				} else if status, ok := value.(Status); ok {
-					if _, err := ValidateStatus(string(status)); err != nil {
-						return nil, err
+			}
+
+			// Update timestamp and save, stopping the timer of finished tasks
+			// and spawning the next occurrence of recurring ones
+			task.UpdatedAt = time.Now()
+			if ts.workflow.IsDone(task.Status) {
+				stopRunning(&task, task.UpdatedAt)
+				if !ts.workflow.IsDone(before) && task.Recurrence != nil && task.NextID == "" {
+					if err := ts.spawnNext(tx, &task, task.UpdatedAt); err != nil {
+						return err
 					}
-					ts.tasks[idx].Status = status
 				}
 			}
+			updated = task
+			return tx.Put(task)
+		})
+		if errors.Is(err, ErrTaskNotFound) || errors.Is(err, ErrValidation) || errors.Is(err, ErrNoUpdatesProvided) ||
+			errors.Is(err, ErrTransitionNotAllowed) {
+			return nil, err
 		}
-
-		// Update timestamp and save
-		ts.tasks[idx].UpdatedAt = time.Now()
-		if err := ts.saveToFile(); err != nil {
+		if err != nil {
 			return nil, fmt.Errorf("failed to save updates: %w", err)
 		}
 
-		return &ts.tasks[idx], nil
+		return &updated, nil
 	}
 }
 
+// DeleteTask deletes the task with the given ID. It refuses to delete a task
+// having subtasks, see DeleteTaskWithPolicy.
 func (ts *TaskStorage) DeleteTask(ctx context.Context, id string) error {
+	return ts.DeleteTaskWithPolicy(ctx, id, DeleteRefuse)
+}
+
+// DeleteTaskWithPolicy deletes the task with the given ID, handling its
+// subtasks as policy says. Everything happens in a single operation.
+func (ts *TaskStorage) DeleteTaskWithPolicy(ctx context.Context, id string, policy DeletePolicy) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
@@ -148,18 +221,45 @@ func (ts *TaskStorage) DeleteTask(ctx context.Context, id string) error {
 		ts.mu.Lock()
 		defer ts.mu.Unlock()
 
-		idx, _, err := ts.findTaskById(id)
-		if err != nil {
-			return err
-		}
-
-		ts.tasks = append(ts.tasks[:idx], ts.tasks[idx+1:]...)
-
-		if err := ts.saveToFile(); err != nil {
-			return err
-		}
-
-		return nil
+		return ts.transaction(OpDelete, func(tx Store) error {
+			deleted, err := tx.Get(id)
+			if err != nil {
+				return err
+			}
+			tasks, err := tx.List()
+			if err != nil {
+				return err
+			}
+			children := childrenOf(tasks)
+
+			now := time.Now()
+			removed := map[string]bool{id: true}
+			switch policy {
+			case DeleteCascade:
+				for _, t := range descendants(children, id) {
+					if err := tx.Delete(t.ID); err != nil {
+						return err
+					}
+					removed[t.ID] = true
+				}
+			case DeleteReparent:
+				for _, t := range children[id] {
+					t.ParentID = deleted.ParentID
+					t.UpdatedAt = now
+					if err := tx.Put(t); err != nil {
+						return err
+					}
+				}
+			default:
+				if n := len(children[id]); n > 0 {
+					return fmt.Errorf("%w: task %s has %d, use the cascade or reparent policy", ErrHasChildren, id, n)
+				}
+			}
+			if err := tx.Delete(id); err != nil {
+				return err
+			}
+			return dropDependencies(tx, removed, now)
+		})
 	}
 }
 
@@ -176,89 +276,105 @@ func (ts *TaskStorage) ListTasksByStatus(status Status) []Task {
 	return filteredTasks
 }
 
+// ClearTasks deletes every task in a single operation, which 'undo' can revert
 func (ts *TaskStorage) ClearTasks() error {
 	ts.mu.Lock()
 	defer ts.mu.Unlock()
 
-	fmt.Print("Are you sure you want to delete all tasks? This action cannot be undone (y/n): ")
-	var response string
-	fmt.Scanln(&response)
-
-	if response != "y" && response != "Y" {
-		return fmt.Errorf("operation cancelled by user")
-	}
-
-	ts.tasks = []Task{}
-	if err := ts.saveToFile(); err != nil {
-		return fmt.Errorf("error deleting tasks: %v", err)
+	err := ts.transaction(OpClear, func(tx Store) error {
+		tasks, err := tx.List()
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			if err := tx.Delete(task.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting tasks: %w", err)
 	}
 
-	fmt.Println("All tasks have been successfully deleted")
 	return nil
 }
 
-func (ts *TaskStorage) loadFromFile() error {
-	ts.mu.RLock()
-	defer ts.mu.RUnlock()
-
-	if _, err := os.Stat(ts.filePath); err != nil {
-		if os.IsNotExist(err) {
-			return ts.saveToFile()
+// transaction runs fn in a store transaction and refreshes the in-memory
+// snapshot afterwards. An automatic backup of the state before the change is
+// taken first when one is due. Field changes are appended to the history of
+// each task written by fn and, unless kind is empty, the tasks touched are
+// recorded in the journal as an operation of that kind so it can be undone.
+// The journal is written while the store is locked and a failure to write it
+// fails the transaction. Callers must hold ts.mu.
+func (ts *TaskStorage) transaction(kind string, fn func(tx Store) error) error {
+	ts.pending = nil
+	err := ts.store.Transaction(func(tx Store) error {
+		ts.autoBackup(tx, time.Now())
+
+		var recorder *recordingStore
+		if kind != "" {
+			recorder = newRecordingStore(tx)
+			tx = recorder
+		}
+		if err := fn(&historyStore{Store: tx, actor: ts.actor(), now: time.Now()}); err != nil {
+			return err
 		}
-		return fmt.Errorf("checking file status: %w", err)
-	}
 
-	data, err := os.ReadFile(ts.filePath)
+		if recorder != nil {
+			if changes := recorder.result(); len(changes) > 0 {
+				return ts.journalize(Operation{Kind: kind, Changes: changes})
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("error reading the file: %v", err)
-	}
-
-	if len(data) == 0 {
-		fmt.Println("file is empty, creating a new one...")
-		return ts.saveToFile()
-	}
-
-	if err := json.Unmarshal(data, &ts.tasks); err != nil {
-		fmt.Println("error deserializing file, it may be corrupt.")
-
-		if err := os.Remove(ts.filePath); err != nil {
-			return fmt.Errorf("error removing corrupted file: %v", err)
+		// The store discarded the changes, so must the journal
+		for i := len(ts.pending) - 1; i >= 0; i-- {
+			if derr := ts.journal.discard(ts.pending[i].op, ts.pending[i].offset); derr != nil {
+				logger.Error("failed to remove operation from journal", zap.Int64("seq", ts.pending[i].op.Seq), zap.Error(derr))
+			}
 		}
-
-		fmt.Println("corrupted file removed, creating a new one...")
-
-		return ts.saveToFile()
+		ts.pending = nil
+		return err
 	}
+	ts.pending = nil
 
-	return nil
+	return ts.refresh()
 }
 
-func (ts *TaskStorage) saveToFile() error {
-	data, err := json.MarshalIndent(ts.tasks, "", "  ")
-	if err != nil {
-		return fmt.Errorf("error serializing tasks: %v", err)
-	}
+// journaled is a journal entry written by the running transaction
+type journaled struct {
+	op     Operation
+	offset int64
+}
 
-	err = os.WriteFile(ts.filePath, data, 0644)
+// journalize appends op to the journal from within a transaction, see
+// transaction
+func (ts *TaskStorage) journalize(op Operation) error {
+	op, offset, err := ts.journal.append(op)
 	if err != nil {
-		return fmt.Errorf("error writing on the file: %v", err)
+		return fmt.Errorf("error recording the operation in the journal: %w", err)
 	}
-
-	fmt.Println("Task saved successfully on the file:", ts.filePath)
-
+	ts.pending = append(ts.pending, journaled{op: op, offset: offset})
 	return nil
 }
 
-func (ts *TaskStorage) PrintTasks() {
-	for _, task := range ts.tasks {
-		fmt.Printf("------\nID: %s\nTitle: %s\nDescription: %s\nStatus: %s\nCreated: %s\nUpdated: %s\n------\n\n",
-			task.ID, task.Title, task.Description, task.Status, task.CreatedAt.Format(time.RFC3339),
-			task.UpdatedAt.Format(time.RFC3339))
+// refresh reloads the in-memory snapshot from the store
+func (ts *TaskStorage) refresh() error {
+	tasks, err := ts.store.List()
+	if err != nil {
+		return fmt.Errorf("error loading tasks: %w", err)
 	}
+	ts.tasks = tasks
+	ts.index = nil
+	return nil
 }
 
-func (ts *TaskStorage) PrintTask(t Task) {
-	fmt.Printf("------\nID: %s\nTitle: %s\nDescription: %s\nStatus: %s\nCreated: %s\nUpdated: %s\n------\n\n",
-		t.ID, t.Title, t.Description, t.Status, t.CreatedAt.Format(time.RFC3339),
-		t.UpdatedAt.Format(time.RFC3339))
+// dateFormat returns the configured layout used to parse dates
+func (ts *TaskStorage) dateFormat() string {
+	if ts.cfg.Task.DateFormat == "" {
+		return time.RFC3339
+	}
+	return ts.cfg.Task.DateFormat
 }
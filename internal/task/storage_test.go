@@ -2,18 +2,26 @@ package task
 
 import (
 	"context"
+	"errors"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/Eddy-Nio/task-tracker-cli/config"
 )
 
+func ptr[T any](v T) *T {
+	return &v
+}
+
 func setupTestStorage(t *testing.T) (*TaskStorage, string) {
-	// Create a temporary file for testing
-	tmpFile, err := os.CreateTemp("", "tasks_test_*.json")
+	// Create a temporary file for testing; its directory also receives the backups
+	tmpFile, err := os.CreateTemp(t.TempDir(), "tasks_test_*.json")
 	if err != nil {
 		t.Fatalf("Failed to create temp file: %v", err)
 	}
+	tmpFile.Close()
 
 	ts, err := NewTaskStorage(tmpFile.Name())
 	if err != nil {
@@ -202,75 +210,163 @@ func TestTaskStorage_UpdateTask(t *testing.T) {
 	scenarios := []struct {
 		name        string
 		taskID      string
-		updates     map[string]interface{}
-		expectError bool
+		patch       TaskPatch
+		expectError error
 	}{
 		{
 			name:   "Valid update - title only",
 			taskID: originalTask.ID,
-			updates: map[string]interface{}{
-				"title": "Updated Title",
+			patch: TaskPatch{
+				Title: ptr("Updated Title"),
 			},
-			expectError: false,
 		},
 		{
 			name:   "Valid update - status",
 			taskID: originalTask.ID,
-			updates: map[string]interface{}{
-				"status": StatusInProgress,
+			patch: TaskPatch{
+				Status: ptr(StatusInProgress),
+			},
+		},
+		{
+			name:   "Valid update - status alias",
+			taskID: originalTask.ID,
+			patch: TaskPatch{
+				Status: ptr(Status("d")),
 			},
-			expectError: false,
 		},
 		{
 			name:   "Invalid task ID",
 			taskID: "non-existent-id",
-			updates: map[string]interface{}{
-				"title": "Updated Title",
+			patch: TaskPatch{
+				Title: ptr("Updated Title"),
 			},
-			expectError: true,
+			expectError: ErrTaskNotFound,
 		},
 		{
 			name:   "Invalid status update",
 			taskID: originalTask.ID,
-			updates: map[string]interface{}{
-				"status": "INVALID_STATUS",
+			patch: TaskPatch{
+				Status: ptr(Status("INVALID_STATUS")),
 			},
-			expectError: true,
+			expectError: ErrValidation,
+		},
+		{
+			name:   "Title too long",
+			taskID: originalTask.ID,
+			patch: TaskPatch{
+				Title: ptr(strings.Repeat("x", config.DefaultConfig.Task.MaxTitleLength+1)),
+			},
+			expectError: ErrValidation,
+		},
+		{
+			name:   "Description too long",
+			taskID: originalTask.ID,
+			patch: TaskPatch{
+				Description: ptr(strings.Repeat("x", config.DefaultConfig.Task.MaxDescriptionLength+1)),
+			},
+			expectError: ErrValidation,
+		},
+		{
+			name:        "Empty patch",
+			taskID:      originalTask.ID,
+			patch:       TaskPatch{},
+			expectError: ErrNoUpdatesProvided,
 		},
 	}
 
 	for _, scenario := range scenarios {
 		t.Run(scenario.name, func(t *testing.T) {
-			updatedTask, err := ts.UpdateTask(context.Background(), scenario.taskID, scenario.updates)
+			before, _ := ts.GetTask(originalTask.ID)
+			updatedTask, err := ts.UpdateTask(context.Background(), scenario.taskID, scenario.patch)
 
-			if scenario.expectError && err == nil {
-				t.Errorf("Expected error for scenario %s, got nil", scenario.name)
+			if scenario.expectError != nil {
+				if !errors.Is(err, scenario.expectError) {
+					t.Errorf("Expected error %v for scenario %s, got %v", scenario.expectError, scenario.name, err)
+				}
+				if after, _ := ts.GetTask(originalTask.ID); after.Title != before.Title || after.Description != before.Description {
+					t.Error("Failed update must leave the stored task unchanged")
+				}
+				return
 			}
 
-			if !scenario.expectError {
-				if err != nil {
-					t.Errorf("Unexpected error: %v", err)
-				}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
 
-				for field, value := range scenario.updates {
-					switch field {
-					case "title":
-						if updatedTask.Title != value {
-							t.Errorf("Expected title %v, got %v", value, updatedTask.Title)
-						}
-					case "status":
-						if updatedTask.Status != value {
-							t.Errorf("Expected status %v, got %v", value, updatedTask.Status)
-						}
-					}
+			if scenario.patch.Title != nil && updatedTask.Title != *scenario.patch.Title {
+				t.Errorf("Expected title %v, got %v", *scenario.patch.Title, updatedTask.Title)
+			}
+			if scenario.patch.Status != nil {
+				want, _ := ValidateStatus(string(*scenario.patch.Status))
+				if updatedTask.Status != want {
+					t.Errorf("Expected status %v, got %v", want, updatedTask.Status)
 				}
+			}
 
-				if !updatedTask.UpdatedAt.After(originalTask.UpdatedAt) {
-					t.Error("UpdatedAt should be more recent than original task")
-				}
+			if !updatedTask.UpdatedAt.After(originalTask.UpdatedAt) {
+				t.Error("UpdatedAt should be more recent than original task")
 			}
 		})
 	}
+
+	var validationErr *ValidationError
+	_, err = ts.UpdateTask(context.Background(), originalTask.ID, TaskPatch{Status: ptr(Status("nope"))})
+	if !errors.As(err, &validationErr) || validationErr.Field != "status" {
+		t.Errorf("Expected a ValidationError on the status field, got %v", err)
+	}
+}
+
+// failingStore runs transactions but reports that persisting them failed
+type failingStore struct {
+	Store
+	fail bool
+}
+
+func (f *failingStore) Transaction(fn func(tx Store) error) error {
+	if !f.fail {
+		return f.Store.Transaction(fn)
+	}
+	return f.Store.Transaction(func(tx Store) error {
+		if err := fn(tx); err != nil {
+			return err
+		}
+		return errors.New("disk full")
+	})
+}
+
+func TestTaskStorage_UpdateTaskSaveFailure(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	inner, err := OpenStore(cfg.Storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &failingStore{Store: inner}
+	ts, err := NewTaskStorageWithStore(store, &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	added, err := ts.AddTask("Unchanged", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.fail = true
+	if _, err := ts.UpdateTask(context.Background(), added.ID, TaskPatch{Title: ptr("Changed")}); err == nil {
+		t.Fatal("Expected save error")
+	}
+
+	task, _ := ts.GetTask(added.ID)
+	if task.Title != "Unchanged" {
+		t.Errorf("Expected in-memory task to be rolled back, got %q", task.Title)
+	}
+	stored, _ := inner.Get(added.ID)
+	if stored.Title != "Unchanged" {
+		t.Errorf("Expected stored task to be unchanged, got %q", stored.Title)
+	}
+	if entries, _ := ts.History(0); len(entries) != 1 || entries[0].Kind != OpAdd {
+		t.Errorf("Expected the failed update to be removed from the journal, got %+v", entries)
+	}
 }
 
 func TestTaskStorage_DeleteTask(t *testing.T) {
@@ -341,8 +437,8 @@ func TestListTasksByStatus(t *testing.T) {
 		t.Fatalf("Error adding task2: %v", err)
 	}
 
-	_, err = ts.UpdateTask(context.Background(), task2.ID, map[string]interface{}{
-		"status": StatusInProgress,
+	_, err = ts.UpdateTask(context.Background(), task2.ID, TaskPatch{
+		Status: ptr(StatusInProgress),
 	})
 	if err != nil {
 		t.Fatalf("Error updating task2 status: %v", err)
@@ -360,22 +456,11 @@ func TestListTasksByStatus(t *testing.T) {
 }
 
 func TestClearTasks(t *testing.T) {
-	storage, _ := NewTaskStorage("test_tasks.json")
-	defer os.Remove("test_tasks.json")
+	storage, _ := NewTaskStorage(filepath.Join(t.TempDir(), "test_tasks.json"))
 
 	storage.AddTask("Test Task", "Test Description")
 
-	oldStdin := os.Stdin
-	r, w, _ := os.Pipe()
-	os.Stdin = r
-
-	go func() {
-		w.Write([]byte("y\n"))
-		w.Close()
-	}()
-
 	err := storage.ClearTasks()
-	os.Stdin = oldStdin
 
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
@@ -386,15 +471,3 @@ func TestClearTasks(t *testing.T) {
 		t.Errorf("Expected 0 tasks after clearing, got %d", len(tasks))
 	}
 }
-
-func TestPrintTasks(t *testing.T) {
-	storage, _ := NewTaskStorage("test_tasks.json")
-	defer os.Remove("test_tasks.json")
-
-	task, _ := NewTask("Test Task", "Test Description")
-	storage.AddTask(task.Title, task.Description)
-
-	storage.PrintTasks()
-
-	storage.PrintTask(*task)
-}
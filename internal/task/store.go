@@ -0,0 +1,47 @@
+package task
+
+import (
+	"fmt"
+
+	"github.com/Eddy-Nio/task-tracker-cli/config"
+)
+
+// Store is a persistence backend for tasks.
+//
+// Get, List, Put and Delete outside of a transaction are persisted
+// immediately. Transaction groups several changes so that backends can
+// persist them at once, e.g. a single rewrite of the JSON file.
+type Store interface {
+	// Get returns the task with the given ID or ErrTaskNotFound
+	Get(id string) (Task, error)
+	// List returns all tasks in insertion order
+	List() ([]Task, error)
+	// Put inserts the task, or replaces the stored task with the same ID
+	Put(t Task) error
+	// Delete removes the task with the given ID or returns ErrTaskNotFound
+	Delete(id string) error
+	// Sequence returns the highest sequential ID ever issued, 0 if none.
+	// Unlike the IDs of the stored tasks it never goes down, so deleted IDs
+	// are not issued again.
+	Sequence() (int, error)
+	// SetSequence records n as the highest sequential ID issued
+	SetSequence(n int) error
+	// Transaction runs fn against a transactional view of the store. Changes
+	// made through tx are persisted if fn returns nil and discarded otherwise.
+	Transaction(fn func(tx Store) error) error
+	// Close releases the resources held by the store
+	Close() error
+}
+
+// OpenStore opens the backend selected by cfg.Backend at cfg.FilePath
+func OpenStore(cfg config.StorageConfig) (Store, error) {
+	switch cfg.Backend {
+	case "", config.BackendJSON:
+		return newJSONStore(cfg)
+	case config.BackendSQLite:
+		return newSQLiteStore(cfg.FilePath)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q. Use one of: %s, %s",
+			cfg.Backend, config.BackendJSON, config.BackendSQLite)
+	}
+}
@@ -0,0 +1,273 @@
+package task
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/config"
+	"github.com/Eddy-Nio/task-tracker-cli/internal/fsutil"
+)
+
+// testConfig returns the default config with the tasks file and backups in a temporary directory
+func testConfig(t *testing.T, file string) config.Config {
+	dir := t.TempDir()
+	cfg := config.DefaultConfig
+	cfg.Storage.FilePath = filepath.Join(dir, file)
+	cfg.Storage.BackupDir = filepath.Join(dir, "backups")
+	return cfg
+}
+
+func openTestStores(t *testing.T) map[string]Store {
+	dir := t.TempDir()
+	stores := map[string]Store{}
+
+	for backend, file := range map[string]string{
+		config.BackendJSON:   "tasks.json",
+		config.BackendSQLite: "tasks.db",
+	} {
+		store, err := OpenStore(config.StorageConfig{Backend: backend, FilePath: filepath.Join(dir, file)})
+		if err != nil {
+			t.Fatalf("Failed to open %s store: %v", backend, err)
+		}
+		t.Cleanup(func() { store.Close() })
+		stores[backend] = store
+	}
+
+	return stores
+}
+
+func TestStore_Backends(t *testing.T) {
+	now := time.Now()
+	first := Task{ID: "aaa", Title: "First", Status: StatusTodo, CreatedAt: now, UpdatedAt: now}
+	second := Task{ID: "bbb", Title: "Second", Status: StatusDone, CreatedAt: now, UpdatedAt: now}
+
+	for backend, store := range openTestStores(t) {
+		t.Run(backend, func(t *testing.T) {
+			if err := store.Put(first); err != nil {
+				t.Fatalf("Put failed: %v", err)
+			}
+			if err := store.Put(second); err != nil {
+				t.Fatalf("Put failed: %v", err)
+			}
+
+			first.Title = "First (edited)"
+			if err := store.Put(first); err != nil {
+				t.Fatalf("Put of existing task failed: %v", err)
+			}
+
+			tasks, err := store.List()
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if len(tasks) != 2 || tasks[0].ID != "aaa" || tasks[1].ID != "bbb" {
+				t.Fatalf("Expected [aaa bbb] in insertion order, got %v", tasks)
+			}
+			if tasks[0].Title != "First (edited)" {
+				t.Errorf("Expected replaced title, got %q", tasks[0].Title)
+			}
+
+			if err := store.Delete("bbb"); err != nil {
+				t.Fatalf("Delete failed: %v", err)
+			}
+			if _, err := store.Get("bbb"); !errors.Is(err, ErrTaskNotFound) {
+				t.Errorf("Expected ErrTaskNotFound after delete, got %v", err)
+			}
+			if err := store.Delete("bbb"); !errors.Is(err, ErrTaskNotFound) {
+				t.Errorf("Expected ErrTaskNotFound deleting missing task, got %v", err)
+			}
+		})
+	}
+}
+
+func TestStore_Sequence(t *testing.T) {
+	for backend, store := range openTestStores(t) {
+		t.Run(backend, func(t *testing.T) {
+			if n, err := store.Sequence(); err != nil || n != 0 {
+				t.Fatalf("Expected an empty sequence, got %d (%v)", n, err)
+			}
+			if err := store.SetSequence(3); err != nil {
+				t.Fatalf("SetSequence failed: %v", err)
+			}
+
+			err := store.Transaction(func(tx Store) error {
+				if err := tx.SetSequence(4); err != nil {
+					return err
+				}
+				return errors.New("boom")
+			})
+			if err == nil {
+				t.Fatal("Expected the transaction error")
+			}
+			if n, err := store.Sequence(); err != nil || n != 3 {
+				t.Errorf("Expected the sequence to be rolled back to 3, got %d (%v)", n, err)
+			}
+		})
+	}
+}
+
+func TestStore_TransactionRollback(t *testing.T) {
+	now := time.Now()
+	task := Task{ID: "ccc", Title: "Keep", Status: StatusTodo, CreatedAt: now, UpdatedAt: now}
+
+	for backend, store := range openTestStores(t) {
+		t.Run(backend, func(t *testing.T) {
+			if err := store.Put(task); err != nil {
+				t.Fatalf("Put failed: %v", err)
+			}
+
+			failure := errors.New("boom")
+			err := store.Transaction(func(tx Store) error {
+				if err := tx.Delete("ccc"); err != nil {
+					return err
+				}
+				if err := tx.Put(Task{ID: "ddd", Title: "Discarded", Status: StatusTodo, CreatedAt: now, UpdatedAt: now}); err != nil {
+					return err
+				}
+				return failure
+			})
+			if !errors.Is(err, failure) {
+				t.Fatalf("Expected transaction error, got %v", err)
+			}
+
+			tasks, err := store.List()
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if len(tasks) != 1 || tasks[0].ID != "ccc" {
+				t.Errorf("Expected rolled back store to hold only ccc, got %v", tasks)
+			}
+		})
+	}
+}
+
+func TestNewTaskStorageWithConfig_SQLite(t *testing.T) {
+	cfg := testConfig(t, "tasks.db")
+	cfg.Storage.Backend = config.BackendSQLite
+
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatalf("Failed to create TaskStorage: %v", err)
+	}
+
+	added, err := ts.AddTask("Persisted", "In sqlite")
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+	ts.Close()
+
+	reopened, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatalf("Failed to reopen TaskStorage: %v", err)
+	}
+	defer reopened.Close()
+
+	got, err := reopened.GetTask(added.ID)
+	if err != nil {
+		t.Fatalf("Expected task to survive reopen: %v", err)
+	}
+	if got.Title != "Persisted" {
+		t.Errorf("Expected title Persisted, got %q", got.Title)
+	}
+
+	cfg.Storage.Backend = "csv"
+	if _, err := NewTaskStorageWithConfig(&cfg); err == nil {
+		t.Error("Expected error for unknown backend")
+	}
+}
+
+func TestJSONStore_ConcurrentInstances(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+
+	// Each storage has its own file handle and in-memory copy, like separate CLI processes
+	const writers = 8
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		go func() {
+			ts, err := NewTaskStorageWithConfig(&cfg)
+			if err != nil {
+				errs <- err
+				return
+			}
+			_, err = ts.AddTask("Concurrent task", "Added in parallel")
+			errs <- err
+		}()
+	}
+	for i := 0; i < writers; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(ts.ListTasks()); got != writers {
+		t.Errorf("Expected %d tasks, got %d: concurrent writers lost updates", writers, got)
+	}
+
+	// The journal entries are numbered while the file is locked
+	ops, err := NewJournal(cfg.Storage.FilePath + ".journal").Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, op := range ops {
+		if op.Seq != int64(i+1) {
+			t.Fatalf("Expected journal entries numbered 1 to %d, got %d at %d", writers, op.Seq, i)
+		}
+	}
+	if len(ops) != writers {
+		t.Errorf("Expected %d journal entries, got %d", writers, len(ops))
+	}
+}
+
+func TestJSONStore_LockTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("advisory file locks are only implemented on unix")
+	}
+
+	cfg := testConfig(t, "tasks.json")
+	cfg.Storage.LockTimeout = 50 * time.Millisecond
+
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := fsutil.AcquireLock(cfg.Storage.FilePath+".lock", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lock.Release()
+
+	if _, err := ts.AddTask("Blocked", "Lock is held"); !errors.Is(err, ErrStorageLocked) {
+		t.Errorf("Expected ErrStorageLocked, got %v", err)
+	}
+}
+
+func TestJSONStore_LegacyTasksDefaultPriority(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	legacy := `[{"id":"abcd1234","title":"Old task","description":"","status":"TODO",` +
+		`"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}]`
+	if err := os.WriteFile(cfg.Storage.FilePath, []byte(legacy), 0644); err != nil {
+		t.Fatalf("Failed to write legacy file: %v", err)
+	}
+
+	storage, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatalf("Failed to load legacy file: %v", err)
+	}
+	defer storage.Close()
+
+	task, err := storage.GetTask("abcd1234")
+	if err != nil {
+		t.Fatalf("GetTask failed: %v", err)
+	}
+	if task.Priority != PriorityMedium {
+		t.Errorf("Expected legacy task to default to %s, got %q", PriorityMedium, task.Priority)
+	}
+}
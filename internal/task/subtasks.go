@@ -0,0 +1,124 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrHasChildren is returned when deleting a task with subtasks under DeleteRefuse
+var ErrHasChildren = errors.New("task has subtasks")
+
+// DeletePolicy decides what DeleteTask does with the subtasks of a task
+type DeletePolicy string
+
+const (
+	DeleteRefuse   DeletePolicy = "refuse"   // Fail with ErrHasChildren
+	DeleteCascade  DeletePolicy = "cascade"  // Delete every subtask too
+	DeleteReparent DeletePolicy = "reparent" // Move the subtasks to the parent of the deleted task
+)
+
+// ParseDeletePolicy validates a delete policy name
+func ParseDeletePolicy(s string) (DeletePolicy, error) {
+	switch p := DeletePolicy(strings.ToLower(s)); p {
+	case DeleteRefuse, DeleteCascade, DeleteReparent:
+		return p, nil
+	}
+	return "", &ValidationError{Field: "policy", Message: fmt.Sprintf("invalid delete policy: %s. Use one of: refuse, cascade, reparent", s)}
+}
+
+// Progress is the roll-up of the subtasks of a task
+type Progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+func (p Progress) String() string {
+	return fmt.Sprintf("%d/%d done", p.Done, p.Total)
+}
+
+// WithParent makes a new task a subtask of the task with the given ID
+func WithParent(id string) TaskOption {
+	return func(t *Task) {
+		t.ParentID = id
+	}
+}
+
+// childrenOf indexes tasks by the ID of their parent
+func childrenOf(tasks []Task) map[string][]Task {
+	children := make(map[string][]Task)
+	for _, t := range tasks {
+		if t.ParentID != "" {
+			children[t.ParentID] = append(children[t.ParentID], t)
+		}
+	}
+	return children
+}
+
+// descendants returns every subtask of id, recursively. Each task is
+// returned once, so a parent cycle in a hand-edited file ends the walk.
+func descendants(children map[string][]Task, id string) []Task {
+	seen := map[string]bool{id: true}
+	var all []Task
+	var walk func(id string)
+	walk = func(id string) {
+		for _, child := range children[id] {
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			all = append(all, child)
+			walk(child.ID)
+		}
+	}
+	walk(id)
+	return all
+}
+
+// RollUp returns the progress of every task having subtasks, counting all of
+// its descendants, keyed by task ID
+func RollUp(tasks []Task, w *Workflow) map[string]Progress {
+	children := childrenOf(tasks)
+	progress := make(map[string]Progress, len(children))
+	for id := range children {
+		var p Progress
+		for _, t := range descendants(children, id) {
+			p.Total++
+			if w.IsDone(t.Status) {
+				p.Done++
+			}
+		}
+		progress[id] = p
+	}
+	return progress
+}
+
+// Progress returns the roll-up of the subtasks of every task, see RollUp
+func (ts *TaskStorage) Progress() map[string]Progress {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	return RollUp(ts.tasks, ts.workflow)
+}
+
+// validateParent checks that the parent of t exists and that t is not its own
+// ancestor
+func validateParent(tx Store, t Task) error {
+	seen := map[string]bool{t.ID: true}
+	for id := t.ParentID; id != ""; {
+		if seen[id] {
+			return &ValidationError{Field: "parent", Message: fmt.Sprintf("task %s cannot be a subtask of %s: it would create a cycle", t.ID, t.ParentID)}
+		}
+		seen[id] = true
+
+		parent, err := tx.Get(id)
+		if errors.Is(err, ErrTaskNotFound) {
+			return &ValidationError{Field: "parent", Message: fmt.Sprintf("parent task %s not found", id)}
+		}
+		if err != nil {
+			return err
+		}
+		id = parent.ParentID
+	}
+	return nil
+}
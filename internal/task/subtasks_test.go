@@ -0,0 +1,173 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestTaskStorage_Subtasks(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	release, err := ts.AddTask("Release", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	build, err := ts.AddTask("Build", "", WithParent(release.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	test, err := ts.AddTask("Test", "", WithParent(build.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.AddTask("Orphan", "", WithParent("missing")); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected validation error for a missing parent, got %v", err)
+	}
+
+	done := StatusDone
+	if _, err := ts.UpdateTask(ctx, test.ID, TaskPatch{Status: &done}); err != nil {
+		t.Fatal(err)
+	}
+	progress := ts.Progress()
+	if p := progress[release.ID]; p != (Progress{Done: 1, Total: 2}) {
+		t.Errorf("Expected release progress 1/2, got %v", p)
+	}
+	if p := progress[build.ID]; p != (Progress{Done: 1, Total: 1}) {
+		t.Errorf("Expected build progress 1/1, got %v", p)
+	}
+	if _, ok := progress[test.ID]; ok {
+		t.Errorf("Expected no progress for a task without subtasks")
+	}
+
+	// Making a task a subtask of its own descendant would create a cycle
+	if _, err := ts.UpdateTask(ctx, release.ID, TaskPatch{ParentID: &test.ID}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected validation error for a cycle, got %v", err)
+	}
+	if _, err := ts.UpdateTask(ctx, release.ID, TaskPatch{ParentID: &release.ID}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected validation error for a task being its own parent, got %v", err)
+	}
+
+	none := ""
+	detached, err := ts.UpdateTask(ctx, test.ID, TaskPatch{ParentID: &none})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detached.ParentID != "" {
+		t.Errorf("Expected task to be detached, got parent %q", detached.ParentID)
+	}
+}
+
+func TestTaskStorage_DeleteTaskWithPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*TaskStorage, []*Task) {
+		cfg := testConfig(t, "tasks.json")
+		ts, err := NewTaskStorageWithConfig(&cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		root, _ := ts.AddTask("Root", "")
+		parent, _ := ts.AddTask("Parent", "", WithParent(root.ID))
+		child, _ := ts.AddTask("Child", "", WithParent(parent.ID))
+		grandchild, _ := ts.AddTask("Grandchild", "", WithParent(child.ID))
+		return ts, []*Task{root, parent, child, grandchild}
+	}
+
+	t.Run("Refuse", func(t *testing.T) {
+		ts, tasks := setup(t)
+		if err := ts.DeleteTask(ctx, tasks[1].ID); !errors.Is(err, ErrHasChildren) {
+			t.Errorf("Expected ErrHasChildren, got %v", err)
+		}
+		if len(ts.ListTasks()) != 4 {
+			t.Errorf("Expected no task to be deleted")
+		}
+		if err := ts.DeleteTask(ctx, tasks[3].ID); err != nil {
+			t.Errorf("Expected a task without subtasks to be deleted, got %v", err)
+		}
+	})
+
+	t.Run("Cascade", func(t *testing.T) {
+		ts, tasks := setup(t)
+		if err := ts.DeleteTaskWithPolicy(ctx, tasks[1].ID, DeleteCascade); err != nil {
+			t.Fatal(err)
+		}
+		remaining := ts.ListTasks()
+		if len(remaining) != 1 || remaining[0].ID != tasks[0].ID {
+			t.Errorf("Expected only the root to remain, got %v", remaining)
+		}
+
+		// The whole cascade is a single operation
+		if _, err := ts.Undo(false); err != nil {
+			t.Fatal(err)
+		}
+		if len(ts.ListTasks()) != 4 {
+			t.Errorf("Expected undo to restore every deleted task")
+		}
+	})
+
+	t.Run("Reparent", func(t *testing.T) {
+		ts, tasks := setup(t)
+		if err := ts.DeleteTaskWithPolicy(ctx, tasks[1].ID, DeleteReparent); err != nil {
+			t.Fatal(err)
+		}
+		child, err := ts.GetTask(tasks[2].ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if child.ParentID != tasks[0].ID {
+			t.Errorf("Expected child to move to %s, got %q", tasks[0].ID, child.ParentID)
+		}
+		grandchild, _ := ts.GetTask(tasks[3].ID)
+		if grandchild.ParentID != tasks[2].ID {
+			t.Errorf("Expected grandchild to keep its parent, got %q", grandchild.ParentID)
+		}
+	})
+}
+
+func TestParseDeletePolicy(t *testing.T) {
+	if p, err := ParseDeletePolicy("Cascade"); err != nil || p != DeleteCascade {
+		t.Errorf("Expected cascade, got %v, %v", p, err)
+	}
+	if _, err := ParseDeletePolicy("orphan"); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
+
+func TestParentCycleInFile(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	// A hand-edited file where 1 and 2 are each other's parent
+	data := `[
+		{"id":"1","title":"One","status":"TODO","parent_id":"2"},
+		{"id":"2","title":"Two","status":"DONE","parent_id":"1"},
+		{"id":"3","title":"Three","status":"TODO","parent_id":"2"}
+	]`
+	if err := os.WriteFile(cfg.Storage.FilePath, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	progress := ts.Progress()
+	if p := progress["1"]; p != (Progress{Done: 1, Total: 2}) {
+		t.Errorf("Expected progress 1/2 for task 1, got %v", p)
+	}
+	if p := progress["2"]; p != (Progress{Done: 0, Total: 2}) {
+		t.Errorf("Expected progress 0/2 for task 2, got %v", p)
+	}
+
+	if err := ts.DeleteTaskWithPolicy(context.Background(), "1", DeleteCascade); err != nil {
+		t.Fatal(err)
+	}
+	if remaining := ts.ListTasks(); len(remaining) != 0 {
+		t.Errorf("Expected the whole cycle to be deleted, got %v", remaining)
+	}
+}
@@ -0,0 +1,184 @@
+package task
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// OpTags is the journal kind of tag renames and merges
+const OpTags = "tags"
+
+// TagCount is the number of tasks carrying a tag
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// WithTags sets the tags of a new task
+func WithTags(tags ...string) TaskOption {
+	return func(t *Task) {
+		t.Tags = append([]string(nil), tags...)
+	}
+}
+
+// NormalizeTag lower-cases and trims a tag, rejecting empty tags and tags
+// containing spaces or commas
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", &ValidationError{Field: "tags", Message: "tag cannot be empty"}
+	}
+	if strings.IndexFunc(tag, func(r rune) bool { return unicode.IsSpace(r) || r == ',' }) >= 0 {
+		return "", &ValidationError{Field: "tags", Message: fmt.Sprintf("invalid tag: %q. Tags cannot contain spaces or commas", tag)}
+	}
+	return tag, nil
+}
+
+// normalizeTags normalizes every tag, dropping duplicates and sorting the result
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool, len(tags))
+	var normalized []string
+	for _, tag := range tags {
+		tag, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// removeTag returns tags without tag
+func removeTag(tags []string, tag string) []string {
+	kept := tags[:0]
+	for _, own := range tags {
+		if own != tag {
+			kept = append(kept, own)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
+
+// HasTag reports whether the task carries the given tag
+func (t Task) HasTag(tag string) bool {
+	for _, own := range t.Tags {
+		if own == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTags reports whether the task carries all of the tags, or any of them when matchAny is set
+func (t Task) HasTags(tags []string, matchAny bool) bool {
+	for _, tag := range tags {
+		has := t.HasTag(tag)
+		if matchAny && has {
+			return true
+		}
+		if !matchAny && !has {
+			return false
+		}
+	}
+	return !matchAny || len(tags) == 0
+}
+
+// TagCounts returns every tag in use with the number of tasks carrying it,
+// sorted by tag
+func (ts *TaskStorage) TagCounts() []TagCount {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, task := range ts.tasks {
+		for _, tag := range task.Tags {
+			counts[tag]++
+		}
+	}
+
+	result := make([]TagCount, 0, len(counts))
+	for tag, count := range counts {
+		result = append(result, TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Tag < result[j].Tag })
+	return result
+}
+
+// RenameTag renames a tag on every task carrying it. Renaming to a tag that
+// is already in use merges the two. It returns the number of tasks changed.
+func (ts *TaskStorage) RenameTag(from, to string) (int, error) {
+	return ts.MergeTags(to, from)
+}
+
+// MergeTags replaces every source tag with target across all tasks in a
+// single save. It returns the number of tasks changed.
+func (ts *TaskStorage) MergeTags(target string, sources ...string) (int, error) {
+	target, err := NormalizeTag(target)
+	if err != nil {
+		return 0, err
+	}
+	replace := make(map[string]bool, len(sources))
+	for _, source := range sources {
+		source, err := NormalizeTag(source)
+		if err != nil {
+			return 0, err
+		}
+		if source != target {
+			replace[source] = true
+		}
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	changed := 0
+	err = ts.transaction(OpTags, func(tx Store) error {
+		tasks, err := tx.List()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, task := range tasks {
+			tags := make([]string, 0, len(task.Tags))
+			found := false
+			for _, tag := range task.Tags {
+				if replace[tag] {
+					tag = target
+					found = true
+				}
+				tags = append(tags, tag)
+			}
+			if !found {
+				continue
+			}
+			if task.Tags, err = normalizeTags(tags); err != nil {
+				return err
+			}
+			task.UpdatedAt = now
+			if err := tx.Put(task); err != nil {
+				return err
+			}
+			changed++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error updating tags: %w", err)
+	}
+
+	return changed, nil
+}
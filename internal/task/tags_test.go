@@ -0,0 +1,102 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNewTask_Tags(t *testing.T) {
+	task, err := NewTask("Tagged", "", WithTags("Backend", " api", "backend"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := []string{"api", "backend"}; !reflect.DeepEqual(task.Tags, expected) {
+		t.Errorf("Expected tags %v, got %v", expected, task.Tags)
+	}
+
+	if _, err := NewTask("Tagged", "", WithTags("two words")); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected validation error for tag with a space, got %v", err)
+	}
+}
+
+func TestTask_HasTags(t *testing.T) {
+	task := Task{Tags: []string{"api", "backend"}}
+
+	scenarios := []struct {
+		name     string
+		tags     []string
+		matchAny bool
+		expected bool
+	}{
+		{"All present", []string{"api", "backend"}, false, true},
+		{"One missing", []string{"api", "frontend"}, false, false},
+		{"Any present", []string{"api", "frontend"}, true, true},
+		{"None present", []string{"docs", "frontend"}, true, false},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			if got := task.HasTags(scenario.tags, scenario.matchAny); got != scenario.expected {
+				t.Errorf("Expected %v, got %v", scenario.expected, got)
+			}
+		})
+	}
+}
+
+func TestTaskStorage_UpdateTags(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	added, err := ts.AddTask("Deploy", "", WithTags("ops"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := ts.UpdateTask(context.Background(), added.ID, TaskPatch{AddTags: []string{"Release"}, RemoveTags: []string{"ops"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := []string{"release"}; !reflect.DeepEqual(updated.Tags, expected) {
+		t.Errorf("Expected tags %v, got %v", expected, updated.Tags)
+	}
+}
+
+func TestTaskStorage_MergeTags(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tags := range [][]string{{"bug", "api"}, {"bugs"}, {"defect", "bug"}, {"docs"}} {
+		if _, err := ts.AddTask("Task", "", WithTags(tags...)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	changed, err := ts.MergeTags("bug", "bugs", "defect")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if changed != 2 {
+		t.Errorf("Expected 2 tasks changed, got %d", changed)
+	}
+
+	expected := []TagCount{{"api", 1}, {"bug", 3}, {"docs", 1}}
+	if counts := ts.TagCounts(); !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected tag counts %v, got %v", expected, counts)
+	}
+
+	// The merge is a single operation, so one undo reverts all of it
+	if _, err := ts.Undo(false); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if counts := ts.TagCounts(); len(counts) != 5 {
+		t.Errorf("Expected the original 5 tags after undo, got %v", counts)
+	}
+}
@@ -3,7 +3,6 @@ package task
 import (
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/Eddy-Nio/task-tracker-cli/config"
@@ -14,7 +13,7 @@ import (
 type Status string
 
 const (
-	// Statuses
+	// Statuses of the default workflow, see Workflow
 	StatusTodo       Status = "TODO"
 	StatusInProgress Status = "IN_PROGRESS"
 	StatusDone       Status = "DONE"
@@ -26,7 +25,11 @@ const (
 )
 
 var (
-	// Status aliases
+	// StatusAliases maps the names and aliases of the default workflow to
+	// their status.
+	//
+	// Deprecated: statuses and aliases are configurable, use
+	// Workflow.ValidateStatus.
 	StatusAliases = map[string]Status{
 		"todo":        StatusTodo,
 		"t":           StatusTodo,
@@ -39,50 +42,120 @@ var (
 
 	// Common errors
 	ErrInvalidTaskID = errors.New("invalid task ID")
+	ErrTaskNotFound  = errors.New("task not found")
 	ErrStorageAccess = errors.New("storage access error")
+	ErrStorageLocked = errors.New("tasks file is locked by another task-tracker process")
 )
 
 // Task represents a task
 type Task struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Status      Status    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          string        `json:"id"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	Status      Status        `json:"status"`
+	Priority    Priority      `json:"priority"`
+	DueAt       *time.Time    `json:"due_at,omitempty"`
+	Reminder    time.Duration `json:"reminder,omitempty"` // How long before the due date to remind, in nanoseconds
+	Tags        []string      `json:"tags,omitempty"`
+	ParentID    string        `json:"parent_id,omitempty"`   // Task this one is a subtask of
+	DependsOn   []string      `json:"depends_on,omitempty"`  // Tasks that must be done before this one
+	Estimate    time.Duration `json:"estimate,omitempty"`    // Expected work, in nanoseconds
+	Work        []WorkEntry   `json:"work,omitempty"`        // Work intervals from timers and logged work
+	Recurrence  *Recurrence   `json:"recurrence,omitempty"`  // Rule the task repeats by once done
+	PreviousID  string        `json:"previous_id,omitempty"` // Occurrence this one repeats
+	NextID      string        `json:"next_id,omitempty"`     // Occurrence spawned when this one was done
+	Aliases     []string      `json:"aliases,omitempty"`     // Former IDs, see MigrateIDs
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	History     []Change      `json:"history,omitempty"`
 }
 
-// NewTask creates a new task
-func NewTask(title, description string) (*Task, error) {
+// TaskOption sets an optional field of a new task
+type TaskOption func(*Task)
+
+// WithPriority sets the priority of a new task
+func WithPriority(p Priority) TaskOption {
+	return func(t *Task) {
+		t.Priority = p
+	}
+}
+
+// WithDueAt sets the due date of a new task
+func WithDueAt(due time.Time) TaskOption {
+	return func(t *Task) {
+		t.DueAt = &due
+	}
+}
+
+// WithReminder sets how long before its due date a new task is announced by
+// the watch command
+func WithReminder(d time.Duration) TaskOption {
+	return func(t *Task) {
+		t.Reminder = d
+	}
+}
+
+// NewTask creates a new task using the default task settings
+func NewTask(title, description string, opts ...TaskOption) (*Task, error) {
+	return NewTaskWithConfig(title, description, config.DefaultConfig.Task, opts...)
+}
+
+// NewTaskWithConfig creates a new task validated against the given task settings
+func NewTaskWithConfig(title, description string, cfg config.TaskConfig, opts ...TaskOption) (*Task, error) {
 	if title == "" {
 		title = "Untitled Task"
 	}
-	if len(title) > config.DefaultConfig.Task.MaxTitleLength {
-		return nil, fmt.Errorf(ErrTitleTooLong, config.DefaultConfig.Task.MaxTitleLength)
+	if len(title) > cfg.MaxTitleLength {
+		return nil, &ValidationError{Field: "title", Message: fmt.Sprintf(ErrTitleTooLong, cfg.MaxTitleLength)}
 	}
 
-	if len(description) > config.DefaultConfig.Task.MaxDescriptionLength {
-		return nil, fmt.Errorf(ErrDescTooLong, config.DefaultConfig.Task.MaxDescriptionLength)
+	if len(description) > cfg.MaxDescriptionLength {
+		return nil, &ValidationError{Field: "description", Message: fmt.Sprintf(ErrDescTooLong, cfg.MaxDescriptionLength)}
 	}
 
-	uuid, err := generateTaskID()
+	// AddTask replaces the ID with one that is unique in the storage
+	id, err := newID(cfg.IDStrategy, 1)
+	if err != nil {
+		return nil, err
+	}
+	workflow, err := workflowOf(cfg)
 	if err != nil {
 		return nil, err
 	}
 
 	now := time.Now()
 
-	return &Task{
-		ID:          uuid,
+	task := &Task{
+		ID:          id,
 		Title:       title,
 		Description: description,
-		Status:      StatusTodo,
+		Status:      workflow.Initial(),
+		Priority:    PriorityMedium,
 		CreatedAt:   now,
 		UpdatedAt:   now,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(task)
+	}
+	if task.Tags, err = normalizeTags(task.Tags); err != nil {
+		return nil, err
+	}
+
+	if err := task.ValidateWithConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// applyDefaults fills in fields missing from tasks saved by older versions
+func (t *Task) applyDefaults() {
+	if t.Priority == "" {
+		t.Priority = PriorityMedium
+	}
 }
 
-// generateTaskID generates a task ID
+// generateTaskID generates a short task ID, see config.IDShort
 func generateTaskID() (string, error) {
 	uuidObject, err := uuid.NewUUID()
 	if err != nil {
@@ -91,37 +164,57 @@ func generateTaskID() (string, error) {
 	return uuidObject.String()[:8], nil
 }
 
-// ValidateStatus validates the status of a task
+// ValidateStatus validates the status of a task against the default workflow.
+// Storages check statuses against the workflow of their config, see
+// TaskStorage.Workflow.
 func ValidateStatus(s string) (Status, error) {
-	normalizedStatus := strings.ToLower(s)
-	if status, exists := StatusAliases[normalizedStatus]; exists {
-		return status, nil
-	}
-	return "", fmt.Errorf("invalid status: %s. Use one of: todo/t, in_progress/ip/p, done/d", s)
+	return DefaultWorkflow.ValidateStatus(s)
 }
 
-// Validate validates the task
+// Validate validates the task using the default task settings
 func (t *Task) Validate() error {
+	return t.ValidateWithConfig(config.DefaultConfig.Task)
+}
+
+// ValidateWithConfig validates the task against the given task settings
+func (t *Task) ValidateWithConfig(cfg config.TaskConfig) error {
 	if t.Title == "" {
-		return fmt.Errorf("title cannot be empty")
+		return &ValidationError{Field: "title", Message: ErrTitleEmpty}
 	}
-	if len(t.Title) > config.DefaultConfig.Task.MaxTitleLength {
-		return fmt.Errorf("title length exceeds maximum of %d characters", config.DefaultConfig.Task.MaxTitleLength)
+	if len(t.Title) > cfg.MaxTitleLength {
+		return &ValidationError{Field: "title", Message: fmt.Sprintf("title length exceeds maximum of %d characters", cfg.MaxTitleLength)}
 	}
-	if len(t.Description) > config.DefaultConfig.Task.MaxDescriptionLength {
-		return fmt.Errorf("description length exceeds maximum of %d characters", config.DefaultConfig.Task.MaxDescriptionLength)
+	if len(t.Description) > cfg.MaxDescriptionLength {
+		return &ValidationError{Field: "description", Message: fmt.Sprintf("description length exceeds maximum of %d characters", cfg.MaxDescriptionLength)}
 	}
 	if t.Status == "" {
-		return fmt.Errorf("status cannot be empty")
+		return &ValidationError{Field: "status", Message: "status cannot be empty"}
+	}
+	workflow, err := workflowOf(cfg)
+	if err != nil {
+		return err
+	}
+	if _, err := workflow.ValidateStatus(string(t.Status)); err != nil {
+		return err
+	}
+	if t.Priority != "" {
+		if _, err := ValidatePriority(string(t.Priority)); err != nil {
+			return err
+		}
+	}
+	if t.Estimate < 0 {
+		return &ValidationError{Field: "estimate", Message: "estimate cannot be negative"}
 	}
-	if _, err := ValidateStatus(string(t.Status)); err != nil {
-		return fmt.Errorf("invalid status: %w", err)
+	if t.Recurrence != nil {
+		if err := t.Recurrence.Validate(); err != nil {
+			return err
+		}
 	}
 	if t.CreatedAt.IsZero() {
-		return fmt.Errorf("created_at cannot be zero")
+		return &ValidationError{Field: "created_at", Message: "created_at cannot be zero"}
 	}
 	if t.UpdatedAt.IsZero() {
-		return fmt.Errorf("updated_at cannot be zero")
+		return &ValidationError{Field: "updated_at", Message: "updated_at cannot be zero"}
 	}
 	return nil
 }
@@ -1,6 +1,7 @@
 package task
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -237,3 +238,149 @@ func TestGenerateTaskID(t *testing.T) {
 		ids[id] = true
 	}
 }
+
+func TestValidatePriority(t *testing.T) {
+	scenarios := []struct {
+		name             string
+		input            string
+		expectedPriority Priority
+		expectError      bool
+	}{
+		{"Valid LOW priority", "low", PriorityLow, false},
+		{"Valid LOW alias", "l", PriorityLow, false},
+		{"Valid MEDIUM alias", "med", PriorityMedium, false},
+		{"Valid HIGH priority", "HIGH", PriorityHigh, false},
+		{"Valid URGENT alias", "u", PriorityUrgent, false},
+		{"Invalid priority", "critical", "", true},
+		{"Empty priority", "", "", true},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			priority, err := ValidatePriority(scenario.input)
+
+			if scenario.expectError && err == nil {
+				t.Errorf("Expected error for input %s, got nil", scenario.input)
+			}
+
+			if !scenario.expectError && err != nil {
+				t.Errorf("Unexpected error for input %s: %v", scenario.input, err)
+			}
+
+			if priority != scenario.expectedPriority {
+				t.Errorf("Expected priority %v, got %v", scenario.expectedPriority, priority)
+			}
+		})
+	}
+}
+
+func TestSortTasks(t *testing.T) {
+	now := time.Now()
+	tasks := []Task{
+		{ID: "low", Priority: PriorityLow, CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: "new-high", Priority: PriorityHigh, CreatedAt: now},
+		{ID: "urgent", Priority: PriorityUrgent, CreatedAt: now},
+		{ID: "old-high", Priority: PriorityHigh, CreatedAt: now.Add(-time.Hour)},
+		{ID: "legacy", CreatedAt: now.Add(-2 * time.Hour)},
+	}
+
+	SortTasks(tasks)
+
+	expected := []string{"urgent", "old-high", "new-high", "legacy", "low"}
+	for i, id := range expected {
+		if tasks[i].ID != id {
+			t.Errorf("Expected task %s at position %d, got %s", id, i, tasks[i].ID)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	// Wednesday
+	now := time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)
+	endOfDay := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 23, 59, 59, 0, time.UTC)
+	}
+
+	scenarios := []struct {
+		name        string
+		input       string
+		expected    time.Time
+		expectError bool
+	}{
+		{"Configured format", "2026-03-10T08:00:00Z", time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC), false},
+		{"Plain date", "2026-03-10", endOfDay(2026, 3, 10), false},
+		{"Today", "today", endOfDay(2026, 3, 4), false},
+		{"Tomorrow", "Tomorrow", endOfDay(2026, 3, 5), false},
+		{"Weekday", "friday", endOfDay(2026, 3, 6), false},
+		{"Next weekday", "next friday", endOfDay(2026, 3, 13), false},
+		{"Next weekday early in the week", "next monday", endOfDay(2026, 3, 9), false},
+		{"Next sunday ends next week", "next sunday", endOfDay(2026, 3, 15), false},
+		{"Same weekday is a week ahead", "next wednesday", endOfDay(2026, 3, 11), false},
+		{"In days", "in 3 days", endOfDay(2026, 3, 7), false},
+		{"In one week", "in 1 week", endOfDay(2026, 3, 11), false},
+		{"In hours", "in 2 hours", now.Add(2 * time.Hour), false},
+		{"Unknown phrase", "someday", time.Time{}, true},
+		{"Empty", "", time.Time{}, true},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			date, err := ParseDate(scenario.input, time.RFC3339, now)
+
+			if scenario.expectError {
+				if !errors.Is(err, ErrValidation) {
+					t.Errorf("Expected validation error for input %q, got %v", scenario.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error for input %q: %v", scenario.input, err)
+			}
+			if !date.Equal(scenario.expected) {
+				t.Errorf("Expected %v, got %v", scenario.expected, date)
+			}
+		})
+	}
+
+	// On a Thursday "friday" is tomorrow but "next friday" is in the next week
+	thursday := now.AddDate(0, 0, 1)
+	if date, _ := ParseDate("friday", time.RFC3339, thursday); !date.Equal(endOfDay(2026, 3, 6)) {
+		t.Errorf("Expected friday to be tomorrow, got %v", date)
+	}
+	if date, _ := ParseDate("next friday", time.RFC3339, thursday); !date.Equal(endOfDay(2026, 3, 13)) {
+		t.Errorf("Expected next friday to be in the next week, got %v", date)
+	}
+
+	// A configured format without a time of day resolves to the end of the day
+	if date, err := ParseDate("10/03/2026", "02/01/2006", now); err != nil || !date.Equal(endOfDay(2026, 3, 10)) {
+		t.Errorf("Expected a date-only configured format to end the day, got %v (%v)", date, err)
+	}
+	if date, err := ParseDate("10/03/2026 08:00", "02/01/2006 15:04", now); err != nil || !date.Equal(time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected a configured format with a time of day to keep it, got %v (%v)", date, err)
+	}
+}
+
+func TestTask_IsOverdue(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	scenarios := []struct {
+		name     string
+		task     Task
+		expected bool
+	}{
+		{"No due date", Task{Status: StatusTodo}, false},
+		{"Due in the future", Task{Status: StatusTodo, DueAt: &future}, false},
+		{"Past due", Task{Status: StatusInProgress, DueAt: &past}, true},
+		{"Past due but done", Task{Status: StatusDone, DueAt: &past}, false},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			if got := scenario.task.IsOverdue(now, DefaultWorkflow); got != scenario.expected {
+				t.Errorf("Expected IsOverdue %v, got %v", scenario.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// OpTime is the journal kind of timer starts and stops and logged work
+const OpTime = "time"
+
+var (
+	// ErrTimerRunning is returned when starting a timer while one is running
+	ErrTimerRunning = errors.New("a timer is already running")
+	// ErrNoTimerRunning is returned when stopping a timer that isn't running
+	ErrNoTimerRunning = errors.New("no timer is running")
+)
+
+// WorkEntry is an interval of work on a task
+type WorkEntry struct {
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end,omitempty"` // Nil while the timer is running
+}
+
+// Duration returns the length of the interval, counting a running one up to now
+func (e WorkEntry) Duration(now time.Time) time.Duration {
+	if e.End == nil {
+		return now.Sub(e.Start)
+	}
+	return e.End.Sub(e.Start)
+}
+
+// WithEstimate sets the estimated work of a new task
+func WithEstimate(d time.Duration) TaskOption {
+	return func(t *Task) {
+		t.Estimate = d
+	}
+}
+
+// Running returns the running work entry of t, or nil
+func (t Task) Running() *WorkEntry {
+	for i := range t.Work {
+		if t.Work[i].End == nil {
+			return &t.Work[i]
+		}
+	}
+	return nil
+}
+
+// TimeSpent returns the work logged on t, counting a running timer up to now
+func (t Task) TimeSpent(now time.Time) time.Duration {
+	var total time.Duration
+	for _, e := range t.Work {
+		total += e.Duration(now)
+	}
+	return total
+}
+
+// Remaining returns the estimated work left on t, which is zero once the
+// estimate is used up. ok is false when t has no estimate.
+func (t Task) Remaining(now time.Time) (remaining time.Duration, ok bool) {
+	if t.Estimate <= 0 {
+		return 0, false
+	}
+	return max(0, t.Estimate-t.TimeSpent(now)), true
+}
+
+// ParseDuration parses a duration such as "1h30m" or "45m"
+func ParseDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil || d < 0 {
+		return 0, &ValidationError{Field: "duration", Message: fmt.Sprintf("invalid duration: %q. Use e.g. 1h30m, 45m or 2h", s)}
+	}
+	return d, nil
+}
+
+// FormatDuration writes d rounded to the minute, e.g. "1h30m", "2h" or "45m"
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h, m := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	}
+	return fmt.Sprintf("%dh%dm", h, m)
+}
+
+// StartTimer starts recording work on the task with the given ID and moves it
+// to the started status of the workflow, see Workflow.Started. Only one timer
+// may run at a time, across all tasks: starting a second one fails with
+// ErrTimerRunning.
+func (ts *TaskStorage) StartTimer(id string) (*Task, error) {
+	return ts.changeWork(id, func(tx Store, t *Task, now time.Time) error {
+		tasks, err := tx.List()
+		if err != nil {
+			return err
+		}
+		for _, other := range tasks {
+			if other.Running() != nil {
+				return fmt.Errorf("%w on task %s", ErrTimerRunning, other.ID)
+			}
+		}
+
+		if status := ts.workflow.Started(); status != "" && t.Status != status {
+			if !ts.workflow.CanTransition(t.Status, status) {
+				return fmt.Errorf("%w: %s -> %s", ErrTransitionNotAllowed, t.Status, status)
+			}
+			t.Status = status
+		}
+		t.Work = append(t.Work, WorkEntry{Start: now})
+		return nil
+	})
+}
+
+// StopTimer stops the running timer of the task with the given ID. It fails
+// with ErrNoTimerRunning when the task has none.
+func (ts *TaskStorage) StopTimer(id string) (*Task, error) {
+	return ts.changeWork(id, func(tx Store, t *Task, now time.Time) error {
+		running := t.Running()
+		if running == nil {
+			return fmt.Errorf("%w on task %s", ErrNoTimerRunning, t.ID)
+		}
+		running.End = &now
+		return nil
+	})
+}
+
+// LogWork records d of work on the task with the given ID, ending now
+func (ts *TaskStorage) LogWork(id string, d time.Duration) (*Task, error) {
+	if d <= 0 {
+		return nil, &ValidationError{Field: "duration", Message: "logged work must be longer than zero"}
+	}
+	return ts.changeWork(id, func(tx Store, t *Task, now time.Time) error {
+		t.Work = append(t.Work, WorkEntry{Start: now.Add(-d), End: &now})
+		return nil
+	})
+}
+
+// RunningTimer returns the task whose timer is running, if any
+func (ts *TaskStorage) RunningTimer() (Task, bool) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	for _, t := range ts.tasks {
+		if t.Running() != nil {
+			return t, true
+		}
+	}
+	return Task{}, false
+}
+
+func (ts *TaskStorage) changeWork(id string, change func(tx Store, t *Task, now time.Time) error) (*Task, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	var updated Task
+	err := ts.transaction(OpTime, func(tx Store) error {
+		t, err := tx.Get(id)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		t.Work = append([]WorkEntry(nil), t.Work...)
+		if err := change(tx, &t, now); err != nil {
+			return err
+		}
+		t.UpdatedAt = now
+		updated = t
+		return tx.Put(t)
+	})
+	if errors.Is(err, ErrTaskNotFound) || errors.Is(err, ErrValidation) || errors.Is(err, ErrTimerRunning) ||
+		errors.Is(err, ErrNoTimerRunning) || errors.Is(err, ErrTransitionNotAllowed) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save work: %w", err)
+	}
+	return &updated, nil
+}
+
+// stopRunning ends the running timer of t, if any, at now
+func stopRunning(t *Task, now time.Time) {
+	if t.Running() == nil {
+		return
+	}
+	t.Work = append([]WorkEntry(nil), t.Work...)
+	t.Running().End = &now
+}
+
+// formatWork summarizes the work on t for history entries
+func formatWork(t Task) string {
+	if len(t.Work) == 0 {
+		return ""
+	}
+	var finished time.Duration
+	for _, e := range t.Work {
+		if e.End != nil {
+			finished += e.Duration(time.Time{})
+		}
+	}
+	summary := FormatDuration(finished)
+	if t.Running() != nil {
+		summary += " (running)"
+	}
+	return summary
+}
+
+func formatEstimate(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return FormatDuration(d)
+}
@@ -0,0 +1,118 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Eddy-Nio/task-tracker-cli/config"
+)
+
+func TestTaskStorage_Timers(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	first, _ := ts.AddTask("First", "", WithEstimate(2*time.Hour))
+	second, _ := ts.AddTask("Second", "")
+
+	started, err := ts.StartTimer(first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if started.Status != StatusInProgress || started.Running() == nil {
+		t.Errorf("Expected a running timer on an IN_PROGRESS task, got %s %+v", started.Status, started.Work)
+	}
+	if _, err := ts.StartTimer(second.ID); !errors.Is(err, ErrTimerRunning) {
+		t.Errorf("Expected ErrTimerRunning for a second timer, got %v", err)
+	}
+	if running, ok := ts.RunningTimer(); !ok || running.ID != first.ID {
+		t.Errorf("Expected the timer of %s to be running, got %v", first.ID, running.ID)
+	}
+
+	if _, err := ts.StopTimer(second.ID); !errors.Is(err, ErrNoTimerRunning) {
+		t.Errorf("Expected ErrNoTimerRunning, got %v", err)
+	}
+	stopped, err := ts.StopTimer(first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stopped.Running() != nil || len(stopped.Work) != 1 {
+		t.Errorf("Expected a single finished work entry, got %+v", stopped.Work)
+	}
+
+	logged, err := ts.LogWork(first.ID, 90*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if spent := logged.TimeSpent(now); spent < 90*time.Minute || spent > 91*time.Minute {
+		t.Errorf("Expected about 1h30m spent, got %v", spent)
+	}
+	if remaining, ok := logged.Remaining(now); !ok || remaining > 30*time.Minute || remaining < 29*time.Minute {
+		t.Errorf("Expected about 30m remaining, got %v (%v)", remaining, ok)
+	}
+	if _, err := ts.LogWork(first.ID, 0); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected a validation error for zero work, got %v", err)
+	}
+
+	// Finishing a task stops its timer
+	if _, err := ts.StartTimer(second.ID); err != nil {
+		t.Fatal(err)
+	}
+	done := StatusDone
+	finished, err := ts.UpdateTask(ctx, second.ID, TaskPatch{Status: &done})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if finished.Running() != nil {
+		t.Errorf("Expected the timer to stop when the task is done")
+	}
+}
+
+func TestTaskStorage_StartTimerWorkflow(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	cfg.Task.Workflow = config.WorkflowConfig{Statuses: []config.StatusConfig{
+		{Name: "BACKLOG"}, {Name: "DOING"}, {Name: "SHIPPED", Done: true},
+	}}
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	added, _ := ts.AddTask("Task", "")
+	started, err := ts.StartTimer(added.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if started.Status != "DOING" {
+		t.Errorf("Expected the started status of the workflow, got %s", started.Status)
+	}
+}
+
+func TestParseAndFormatDuration(t *testing.T) {
+	d, err := ParseDuration("1h30m")
+	if err != nil || d != 90*time.Minute {
+		t.Errorf("Expected 1h30m, got %v (%v)", d, err)
+	}
+	for _, s := range []string{"", "soon", "-1h"} {
+		if _, err := ParseDuration(s); !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected a validation error for %q, got %v", s, err)
+		}
+	}
+
+	for d, expected := range map[time.Duration]string{
+		0:                               "0m",
+		45 * time.Minute:                "45m",
+		2 * time.Hour:                   "2h",
+		90*time.Minute + 20*time.Second: "1h30m",
+	} {
+		if got := FormatDuration(d); got != expected {
+			t.Errorf("FormatDuration(%v) = %q, expected %q", d, got, expected)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Eddy-Nio/task-tracker-cli/config"
+)
+
+// ErrTransitionNotAllowed is returned when the workflow forbids a status change
+var ErrTransitionNotAllowed = errors.New("status transition not allowed")
+
+// Workflow is the set of statuses tasks go through, their aliases and the
+// moves allowed between them, see config.WorkflowConfig
+type Workflow struct {
+	statuses    []Status
+	aliases     map[string]Status          // Lower-cased names and aliases
+	done        map[Status]bool            // Statuses ending the work on a task
+	transitions map[Status]map[Status]bool // Allowed targets, nil for statuses allowing any
+	initial     Status
+	started     Status   // Empty when every status is initial or done
+	usage       []string // Names with their aliases, e.g. "todo/t"
+}
+
+// DefaultWorkflow is the TODO, IN_PROGRESS, DONE workflow used unless the
+// config defines another one
+var DefaultWorkflow = mustWorkflow(config.DefaultConfig.Task.Workflow)
+
+// NewWorkflow builds a workflow from its configuration, checking that names
+// and aliases are unique and that transitions refer to known statuses
+func NewWorkflow(cfg config.WorkflowConfig) (*Workflow, error) {
+	if len(cfg.Statuses) == 0 {
+		return nil, invalidWorkflow("at least one status is required")
+	}
+
+	w := &Workflow{
+		aliases:     make(map[string]Status),
+		done:        make(map[Status]bool),
+		transitions: make(map[Status]map[Status]bool),
+	}
+	for _, sc := range cfg.Statuses {
+		name := Status(strings.ToUpper(strings.TrimSpace(sc.Name)))
+		if name == "" {
+			return nil, invalidWorkflow("status names cannot be empty")
+		}
+		if _, defined := w.done[name]; defined {
+			return nil, invalidWorkflow(fmt.Sprintf("status %s is defined twice", name))
+		}
+		var names []string
+		for _, alias := range append([]string{string(name)}, sc.Aliases...) {
+			alias = strings.ToLower(strings.TrimSpace(alias))
+			if alias == "" {
+				continue
+			}
+			if other, taken := w.aliases[alias]; taken {
+				if other == name {
+					continue
+				}
+				return nil, invalidWorkflow(fmt.Sprintf("%q is used by both %s and %s", alias, other, name))
+			}
+			w.aliases[alias] = name
+			names = append(names, alias)
+		}
+		w.usage = append(w.usage, strings.Join(names, "/"))
+		w.statuses = append(w.statuses, name)
+		w.done[name] = sc.Done
+	}
+
+	w.initial = w.statuses[0]
+	if cfg.Initial != "" {
+		initial, ok := w.lookup(cfg.Initial)
+		if !ok {
+			return nil, invalidWorkflow(fmt.Sprintf("unknown initial status %q", cfg.Initial))
+		}
+		w.initial = initial
+	}
+
+	if cfg.Started != "" {
+		started, ok := w.lookup(cfg.Started)
+		if !ok {
+			return nil, invalidWorkflow(fmt.Sprintf("unknown started status %q", cfg.Started))
+		}
+		if started == w.initial || w.done[started] {
+			return nil, invalidWorkflow(fmt.Sprintf("started status %s cannot be the initial status or a done one", started))
+		}
+		w.started = started
+	} else {
+		for _, s := range w.statuses {
+			if s != w.initial && !w.done[s] {
+				w.started = s
+				break
+			}
+		}
+	}
+
+	for from, targets := range cfg.Transitions {
+		source, ok := w.lookup(from)
+		if !ok {
+			return nil, invalidWorkflow(fmt.Sprintf("unknown status %q in transitions", from))
+		}
+		allowed := make(map[Status]bool, len(targets))
+		for _, to := range targets {
+			target, ok := w.lookup(to)
+			if !ok {
+				return nil, invalidWorkflow(fmt.Sprintf("unknown status %q in transitions from %s", to, source))
+			}
+			allowed[target] = true
+		}
+		w.transitions[source] = allowed
+	}
+
+	return w, nil
+}
+
+func mustWorkflow(cfg config.WorkflowConfig) *Workflow {
+	w, err := NewWorkflow(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+func invalidWorkflow(message string) error {
+	return &ValidationError{Field: "workflow", Message: "invalid workflow: " + message}
+}
+
+// workflowOf returns the workflow configured in cfg, the default one when
+// cfg defines no statuses
+func workflowOf(cfg config.TaskConfig) (*Workflow, error) {
+	if len(cfg.Workflow.Statuses) == 0 {
+		return DefaultWorkflow, nil
+	}
+	return NewWorkflow(cfg.Workflow)
+}
+
+func (w *Workflow) lookup(s string) (Status, bool) {
+	status, ok := w.aliases[strings.ToLower(strings.TrimSpace(s))]
+	return status, ok
+}
+
+// ValidateStatus returns the status named s, accepting names in any case and aliases
+func (w *Workflow) ValidateStatus(s string) (Status, error) {
+	if status, ok := w.lookup(s); ok {
+		return status, nil
+	}
+	return "", &ValidationError{Field: "status", Message: fmt.Sprintf("invalid status: %s. Use one of: %s", s, w.Usage())}
+}
+
+// Usage lists the statuses with their aliases, e.g. "todo/t, done/d"
+func (w *Workflow) Usage() string {
+	return strings.Join(w.usage, ", ")
+}
+
+// Statuses returns the statuses in the order they were defined
+func (w *Workflow) Statuses() []Status {
+	return append([]Status(nil), w.statuses...)
+}
+
+// Initial returns the status of new tasks
+func (w *Workflow) Initial() Status {
+	return w.initial
+}
+
+// Started returns the status of tasks being worked on, IN_PROGRESS by
+// default. It is empty when the workflow has no such status.
+func (w *Workflow) Started() Status {
+	return w.started
+}
+
+// IsDone reports whether s ends the work on a task
+func (w *Workflow) IsDone(s Status) bool {
+	return w.done[s]
+}
+
+// CanTransition reports whether a task may move from one status to another
+// without being forced. Staying in the same status is always allowed.
+func (w *Workflow) CanTransition(from, to Status) bool {
+	allowed, restricted := w.transitions[from]
+	return from == to || !restricted || allowed[to]
+}
@@ -0,0 +1,155 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Eddy-Nio/task-tracker-cli/config"
+)
+
+// teamWorkflow adds BLOCKED, REVIEW and CANCELLED to the default statuses and
+// only lets done tasks go back to review
+var teamWorkflow = config.WorkflowConfig{
+	Statuses: []config.StatusConfig{
+		{Name: "TODO", Aliases: []string{"t"}},
+		{Name: "IN_PROGRESS", Aliases: []string{"ip"}},
+		{Name: "BLOCKED", Aliases: []string{"b"}},
+		{Name: "REVIEW", Aliases: []string{"r"}},
+		{Name: "DONE", Aliases: []string{"d"}, Done: true},
+		{Name: "CANCELLED", Aliases: []string{"c"}, Done: true},
+	},
+	Transitions: map[string][]string{
+		"done":      {"REVIEW"},
+		"CANCELLED": {},
+	},
+}
+
+func TestNewWorkflow(t *testing.T) {
+	w, err := NewWorkflow(teamWorkflow)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if s, err := w.ValidateStatus("Review"); err != nil || s != "REVIEW" {
+		t.Errorf("Expected REVIEW, got %q (%v)", s, err)
+	}
+	if s, err := w.ValidateStatus("c"); err != nil || s != "CANCELLED" {
+		t.Errorf("Expected CANCELLED, got %q (%v)", s, err)
+	}
+	if _, err := w.ValidateStatus("p"); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected a validation error for an alias the workflow lacks, got %v", err)
+	}
+	if w.Initial() != "TODO" || !w.IsDone("CANCELLED") || w.IsDone("REVIEW") {
+		t.Errorf("Unexpected initial or done statuses")
+	}
+
+	transitions := []struct {
+		from, to Status
+		allowed  bool
+	}{
+		{"TODO", "DONE", true},
+		{"DONE", "REVIEW", true},
+		{"DONE", "TODO", false},
+		{"CANCELLED", "TODO", false},
+		{"CANCELLED", "CANCELLED", true},
+	}
+	for _, tr := range transitions {
+		if got := w.CanTransition(tr.from, tr.to); got != tr.allowed {
+			t.Errorf("CanTransition(%s, %s) = %v, expected %v", tr.from, tr.to, got, tr.allowed)
+		}
+	}
+
+	if usage := DefaultWorkflow.Usage(); usage != "todo/t, in_progress/ip/p, done/d" {
+		t.Errorf("Unexpected default usage %q", usage)
+	}
+}
+
+func TestWorkflow_Started(t *testing.T) {
+	if s := DefaultWorkflow.Started(); s != StatusInProgress {
+		t.Errorf("Expected IN_PROGRESS by default, got %q", s)
+	}
+
+	// The first status neither initial nor done, unless configured
+	kanban := config.WorkflowConfig{Statuses: []config.StatusConfig{
+		{Name: "BACKLOG"}, {Name: "DOING"}, {Name: "REVIEW"}, {Name: "SHIPPED", Done: true},
+	}}
+	if w, err := NewWorkflow(kanban); err != nil || w.Started() != "DOING" {
+		t.Errorf("Expected DOING, got %v (%v)", w, err)
+	}
+	kanban.Started = "review"
+	if w, err := NewWorkflow(kanban); err != nil || w.Started() != "REVIEW" {
+		t.Errorf("Expected the configured REVIEW, got %v (%v)", w, err)
+	}
+
+	simple := config.WorkflowConfig{Statuses: []config.StatusConfig{{Name: "OPEN"}, {Name: "CLOSED", Done: true}}}
+	if w, err := NewWorkflow(simple); err != nil || w.Started() != "" {
+		t.Errorf("Expected no started status, got %v (%v)", w, err)
+	}
+}
+
+func TestNewWorkflow_Invalid(t *testing.T) {
+	scenarios := map[string]config.WorkflowConfig{
+		"No statuses":        {},
+		"Duplicate status":   {Statuses: []config.StatusConfig{{Name: "TODO"}, {Name: "todo"}}},
+		"Shared alias":       {Statuses: []config.StatusConfig{{Name: "TODO", Aliases: []string{"x"}}, {Name: "DONE", Aliases: []string{"x"}}}},
+		"Unknown initial":    {Statuses: []config.StatusConfig{{Name: "TODO"}}, Initial: "OPEN"},
+		"Unknown started":    {Statuses: []config.StatusConfig{{Name: "TODO"}}, Started: "DOING"},
+		"Done started":       {Statuses: []config.StatusConfig{{Name: "TODO"}, {Name: "DONE", Done: true}}, Started: "DONE"},
+		"Unknown transition": {Statuses: []config.StatusConfig{{Name: "TODO"}}, Transitions: map[string][]string{"TODO": {"DONE"}}},
+	}
+	for name, cfg := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewWorkflow(cfg); !errors.Is(err, ErrValidation) {
+				t.Errorf("Expected a validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTaskStorage_Workflow(t *testing.T) {
+	cfg := testConfig(t, "tasks.json")
+	cfg.Task.Workflow = teamWorkflow
+	ts, err := NewTaskStorageWithConfig(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	ctx := context.Background()
+
+	// Another storage with the default workflow doesn't affect this one
+	defaultCfg := testConfig(t, "other.json")
+	other, err := NewTaskStorageWithConfig(&defaultCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	if _, err := other.Workflow().ValidateStatus("c"); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected the default workflow to reject \"c\", got %v", err)
+	}
+
+	dep, _ := ts.AddTask("Dependency", "")
+	task, _ := ts.AddTask("Task", "")
+	if _, err := ts.AddDependencies(task.ID, dep.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	cancelled := Status("c")
+	if _, err := ts.UpdateTask(ctx, dep.ID, TaskPatch{Status: &cancelled}); err != nil {
+		t.Fatalf("Expected a move to a configured status, got %v", err)
+	}
+	if blocked := ts.BlockedBy(); len(blocked[task.ID]) != 0 {
+		t.Errorf("Expected a cancelled dependency not to block, got %v", blocked)
+	}
+
+	todo := StatusTodo
+	if _, err := ts.UpdateTask(ctx, dep.ID, TaskPatch{Status: &todo}); !errors.Is(err, ErrTransitionNotAllowed) {
+		t.Errorf("Expected ErrTransitionNotAllowed, got %v", err)
+	}
+	if got, _ := ts.GetTask(dep.ID); got.Status != "CANCELLED" {
+		t.Errorf("Expected the forbidden change not to be saved, got %s", got.Status)
+	}
+	if _, err := ts.UpdateTask(ctx, dep.ID, TaskPatch{Status: &todo, Force: true}); err != nil {
+		t.Errorf("Expected --force to allow the change, got %v", err)
+	}
+}